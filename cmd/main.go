@@ -1,15 +1,136 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
 	"social-service/internal/app"
 	"social-service/internal/config"
+	"social-service/internal/database"
+	"syscall"
+	"time"
 
 	"github.com/viktoralyoshin/utils/pkg/logger"
 )
 
+const usage = `usage: social-service [command] [flags]
+
+commands:
+  serve                           run the gRPC server (default)
+  migrate up|down|status|redo     apply, roll back, list or re-apply migrations
+  create [--dir DIR] NAME         add an empty SQL migration to DIR
+
+Run "social-service serve --help" to list the configuration flags.
+`
+
 func main() {
-	cfg := config.Load()
+	args := os.Args[1:]
+
+	command := "serve"
+	if len(args) > 0 && !isFlag(args[0]) {
+		command, args = args[0], args[1:]
+	}
+
+	switch command {
+	case "serve":
+		serve(args)
+	case "migrate":
+		if len(args) == 0 || isFlag(args[0]) {
+			fail(2, "migrate needs one of up, down, status or redo\n\n%s", usage)
+		}
+		migrate(args[0], args[1:])
+	case "create":
+		create(args)
+	case "help":
+		fmt.Print(usage)
+	default:
+		fail(2, "unknown command %q\n\n%s", command, usage)
+	}
+}
+
+func serve(args []string) {
+	cfg := loadConfig(config.Load, args)
+
+	if cfg.PrintConfig {
+		if err := cfg.Print(os.Stdout); err != nil {
+			fail(1, "failed to print configuration: %v\n", err)
+		}
+		return
+	}
+
 	logger.Setup(cfg.Env)
 
-	app.Start(cfg)
+	if err := app.Start(cfg); err != nil {
+		// Already logged; exit non-zero so the crash is not taken for a
+		// clean stop.
+		os.Exit(1)
+	}
+}
+
+// migrate runs without the server, e.g. as a Kubernetes job ahead of a
+// rollout that sets migrate_on_start to false.
+func migrate(command string, args []string) {
+	cfg := loadConfig(config.LoadDatabase, args)
+
+	logger.Setup(cfg.Env)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	db, err := database.Init(cfg)
+	if err != nil {
+		fail(1, "unable to connect to database: %v\n", err)
+	}
+	defer func() { _ = db.Close() }()
+
+	if err := database.RunMigration(ctx, db, command, os.Stdout); err != nil {
+		fail(1, "migrate %s: %v\n", command, err)
+	}
+}
+
+func create(args []string) {
+	fs := flag.NewFlagSet("social-service create", flag.ContinueOnError)
+	dir := fs.String("dir", "migrations", "directory holding the migration files")
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		os.Exit(2)
+	}
+	if fs.NArg() != 1 {
+		fail(2, "create needs exactly one migration name\n")
+	}
+
+	path, err := database.CreateMigration(*dir, fs.Arg(0), time.Now())
+	if err != nil {
+		fail(1, "create migration: %v\n", err)
+	}
+
+	fmt.Println(path)
+}
+
+func loadConfig(load func([]string) (*config.Config, error), args []string) *config.Config {
+	cfg, err := load(args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+
+		fail(2, "invalid configuration:\n%v\n", err)
+	}
+
+	return cfg
+}
+
+func isFlag(arg string) bool {
+	return len(arg) > 1 && arg[0] == '-'
+}
+
+func fail(code int, format string, a ...any) {
+	fmt.Fprintf(os.Stderr, format, a...)
+	os.Exit(code)
 }
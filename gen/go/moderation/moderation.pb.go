@@ -0,0 +1,1430 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: moderation/moderation.proto
+
+package moderation
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type AppealOutcome int32
+
+const (
+	AppealOutcome_APPEAL_OUTCOME_UNSPECIFIED AppealOutcome = 0
+	AppealOutcome_APPEAL_OUTCOME_UPHELD      AppealOutcome = 1
+	AppealOutcome_APPEAL_OUTCOME_REINSTATED  AppealOutcome = 2
+)
+
+// Enum value maps for AppealOutcome.
+var (
+	AppealOutcome_name = map[int32]string{
+		0: "APPEAL_OUTCOME_UNSPECIFIED",
+		1: "APPEAL_OUTCOME_UPHELD",
+		2: "APPEAL_OUTCOME_REINSTATED",
+	}
+	AppealOutcome_value = map[string]int32{
+		"APPEAL_OUTCOME_UNSPECIFIED": 0,
+		"APPEAL_OUTCOME_UPHELD":      1,
+		"APPEAL_OUTCOME_REINSTATED":  2,
+	}
+)
+
+func (x AppealOutcome) Enum() *AppealOutcome {
+	p := new(AppealOutcome)
+	*p = x
+	return p
+}
+
+func (x AppealOutcome) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AppealOutcome) Descriptor() protoreflect.EnumDescriptor {
+	return file_moderation_moderation_proto_enumTypes[0].Descriptor()
+}
+
+func (AppealOutcome) Type() protoreflect.EnumType {
+	return &file_moderation_moderation_proto_enumTypes[0]
+}
+
+func (x AppealOutcome) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AppealOutcome.Descriptor instead.
+func (AppealOutcome) EnumDescriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{0}
+}
+
+type ShadowBan struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Banned        bool                   `protobuf:"varint,2,opt,name=banned,proto3" json:"banned,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	BannedBy      string                 `protobuf:"bytes,4,opt,name=banned_by,json=bannedBy,proto3" json:"banned_by,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ShadowBan) Reset() {
+	*x = ShadowBan{}
+	mi := &file_moderation_moderation_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ShadowBan) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ShadowBan) ProtoMessage() {}
+
+func (x *ShadowBan) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ShadowBan.ProtoReflect.Descriptor instead.
+func (*ShadowBan) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ShadowBan) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ShadowBan) GetBanned() bool {
+	if x != nil {
+		return x.Banned
+	}
+	return false
+}
+
+func (x *ShadowBan) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ShadowBan) GetBannedBy() string {
+	if x != nil {
+		return x.BannedBy
+	}
+	return ""
+}
+
+func (x *ShadowBan) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type SetShadowBanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Banned        bool                   `protobuf:"varint,2,opt,name=banned,proto3" json:"banned,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetShadowBanRequest) Reset() {
+	*x = SetShadowBanRequest{}
+	mi := &file_moderation_moderation_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetShadowBanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetShadowBanRequest) ProtoMessage() {}
+
+func (x *SetShadowBanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetShadowBanRequest.ProtoReflect.Descriptor instead.
+func (*SetShadowBanRequest) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SetShadowBanRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetShadowBanRequest) GetBanned() bool {
+	if x != nil {
+		return x.Banned
+	}
+	return false
+}
+
+func (x *SetShadowBanRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetShadowBanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShadowBan     *ShadowBan             `protobuf:"bytes,1,opt,name=shadow_ban,json=shadowBan,proto3" json:"shadow_ban,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetShadowBanResponse) Reset() {
+	*x = SetShadowBanResponse{}
+	mi := &file_moderation_moderation_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetShadowBanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetShadowBanResponse) ProtoMessage() {}
+
+func (x *SetShadowBanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetShadowBanResponse.ProtoReflect.Descriptor instead.
+func (*SetShadowBanResponse) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetShadowBanResponse) GetShadowBan() *ShadowBan {
+	if x != nil {
+		return x.ShadowBan
+	}
+	return nil
+}
+
+type GetShadowBanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShadowBanRequest) Reset() {
+	*x = GetShadowBanRequest{}
+	mi := &file_moderation_moderation_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShadowBanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShadowBanRequest) ProtoMessage() {}
+
+func (x *GetShadowBanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShadowBanRequest.ProtoReflect.Descriptor instead.
+func (*GetShadowBanRequest) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetShadowBanRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type GetShadowBanResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ShadowBan     *ShadowBan             `protobuf:"bytes,1,opt,name=shadow_ban,json=shadowBan,proto3" json:"shadow_ban,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetShadowBanResponse) Reset() {
+	*x = GetShadowBanResponse{}
+	mi := &file_moderation_moderation_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetShadowBanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetShadowBanResponse) ProtoMessage() {}
+
+func (x *GetShadowBanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetShadowBanResponse.ProtoReflect.Descriptor instead.
+func (*GetShadowBanResponse) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetShadowBanResponse) GetShadowBan() *ShadowBan {
+	if x != nil {
+		return x.ShadowBan
+	}
+	return nil
+}
+
+type ModeratedReview struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	GameId        string                 `protobuf:"bytes,3,opt,name=game_id,json=gameId,proto3" json:"game_id,omitempty"`
+	Rating        int32                  `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Text          string                 `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModeratedReview) Reset() {
+	*x = ModeratedReview{}
+	mi := &file_moderation_moderation_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModeratedReview) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModeratedReview) ProtoMessage() {}
+
+func (x *ModeratedReview) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModeratedReview.ProtoReflect.Descriptor instead.
+func (*ModeratedReview) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ModeratedReview) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ModeratedReview) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ModeratedReview) GetGameId() string {
+	if x != nil {
+		return x.GameId
+	}
+	return ""
+}
+
+func (x *ModeratedReview) GetRating() int32 {
+	if x != nil {
+		return x.Rating
+	}
+	return 0
+}
+
+func (x *ModeratedReview) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ModeratedReview) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ModeratedReview) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *ModeratedReview) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ModerateReviewRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateReviewRequest) Reset() {
+	*x = ModerateReviewRequest{}
+	mi := &file_moderation_moderation_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateReviewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateReviewRequest) ProtoMessage() {}
+
+func (x *ModerateReviewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateReviewRequest.ProtoReflect.Descriptor instead.
+func (*ModerateReviewRequest) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ModerateReviewRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *ModerateReviewRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ModerateReviewResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Review        *ModeratedReview       `protobuf:"bytes,1,opt,name=review,proto3" json:"review,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerateReviewResponse) Reset() {
+	*x = ModerateReviewResponse{}
+	mi := &file_moderation_moderation_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerateReviewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerateReviewResponse) ProtoMessage() {}
+
+func (x *ModerateReviewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerateReviewResponse.ProtoReflect.Descriptor instead.
+func (*ModerateReviewResponse) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ModerateReviewResponse) GetReview() *ModeratedReview {
+	if x != nil {
+		return x.Review
+	}
+	return nil
+}
+
+type ModerationLogEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ActorId       string                 `protobuf:"bytes,2,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	Action        string                 `protobuf:"bytes,3,opt,name=action,proto3" json:"action,omitempty"`
+	TargetType    string                 `protobuf:"bytes,4,opt,name=target_type,json=targetType,proto3" json:"target_type,omitempty"`
+	TargetId      string                 `protobuf:"bytes,5,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Before        *structpb.Struct       `protobuf:"bytes,7,opt,name=before,proto3" json:"before,omitempty"`
+	After         *structpb.Struct       `protobuf:"bytes,8,opt,name=after,proto3" json:"after,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ModerationLogEntry) Reset() {
+	*x = ModerationLogEntry{}
+	mi := &file_moderation_moderation_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ModerationLogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ModerationLogEntry) ProtoMessage() {}
+
+func (x *ModerationLogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ModerationLogEntry.ProtoReflect.Descriptor instead.
+func (*ModerationLogEntry) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ModerationLogEntry) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ModerationLogEntry) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ModerationLogEntry) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ModerationLogEntry) GetTargetType() string {
+	if x != nil {
+		return x.TargetType
+	}
+	return ""
+}
+
+func (x *ModerationLogEntry) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *ModerationLogEntry) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ModerationLogEntry) GetBefore() *structpb.Struct {
+	if x != nil {
+		return x.Before
+	}
+	return nil
+}
+
+func (x *ModerationLogEntry) GetAfter() *structpb.Struct {
+	if x != nil {
+		return x.After
+	}
+	return nil
+}
+
+func (x *ModerationLogEntry) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListModerationLogRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ActorId       string                 `protobuf:"bytes,1,opt,name=actor_id,json=actorId,proto3" json:"actor_id,omitempty"`
+	TargetId      string                 `protobuf:"bytes,2,opt,name=target_id,json=targetId,proto3" json:"target_id,omitempty"`
+	TargetType    string                 `protobuf:"bytes,3,opt,name=target_type,json=targetType,proto3" json:"target_type,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	From          *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=from,proto3" json:"from,omitempty"`
+	To            *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=to,proto3" json:"to,omitempty"`
+	Limit         int32                  `protobuf:"varint,7,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,8,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModerationLogRequest) Reset() {
+	*x = ListModerationLogRequest{}
+	mi := &file_moderation_moderation_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModerationLogRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModerationLogRequest) ProtoMessage() {}
+
+func (x *ListModerationLogRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModerationLogRequest.ProtoReflect.Descriptor instead.
+func (*ListModerationLogRequest) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListModerationLogRequest) GetActorId() string {
+	if x != nil {
+		return x.ActorId
+	}
+	return ""
+}
+
+func (x *ListModerationLogRequest) GetTargetId() string {
+	if x != nil {
+		return x.TargetId
+	}
+	return ""
+}
+
+func (x *ListModerationLogRequest) GetTargetType() string {
+	if x != nil {
+		return x.TargetType
+	}
+	return ""
+}
+
+func (x *ListModerationLogRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *ListModerationLogRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ListModerationLogRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ListModerationLogRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListModerationLogRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListModerationLogResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Entries       []*ModerationLogEntry  `protobuf:"bytes,1,rep,name=entries,proto3" json:"entries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListModerationLogResponse) Reset() {
+	*x = ListModerationLogResponse{}
+	mi := &file_moderation_moderation_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListModerationLogResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListModerationLogResponse) ProtoMessage() {}
+
+func (x *ListModerationLogResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListModerationLogResponse.ProtoReflect.Descriptor instead.
+func (*ListModerationLogResponse) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListModerationLogResponse) GetEntries() []*ModerationLogEntry {
+	if x != nil {
+		return x.Entries
+	}
+	return nil
+}
+
+type Appeal struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ReviewId       string                 `protobuf:"bytes,2,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	UserId         string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Message        string                 `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	RemovedBy      string                 `protobuf:"bytes,6,opt,name=removed_by,json=removedBy,proto3" json:"removed_by,omitempty"`
+	ResolvedBy     string                 `protobuf:"bytes,7,opt,name=resolved_by,json=resolvedBy,proto3" json:"resolved_by,omitempty"`
+	ResolutionNote string                 `protobuf:"bytes,8,opt,name=resolution_note,json=resolutionNote,proto3" json:"resolution_note,omitempty"`
+	CreatedAt      *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	ResolvedAt     *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Appeal) Reset() {
+	*x = Appeal{}
+	mi := &file_moderation_moderation_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Appeal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Appeal) ProtoMessage() {}
+
+func (x *Appeal) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Appeal.ProtoReflect.Descriptor instead.
+func (*Appeal) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Appeal) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Appeal) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *Appeal) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Appeal) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Appeal) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Appeal) GetRemovedBy() string {
+	if x != nil {
+		return x.RemovedBy
+	}
+	return ""
+}
+
+func (x *Appeal) GetResolvedBy() string {
+	if x != nil {
+		return x.ResolvedBy
+	}
+	return ""
+}
+
+func (x *Appeal) GetResolutionNote() string {
+	if x != nil {
+		return x.ResolutionNote
+	}
+	return ""
+}
+
+func (x *Appeal) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Appeal) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+type FileAppealRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ReviewId      string                 `protobuf:"bytes,1,opt,name=review_id,json=reviewId,proto3" json:"review_id,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileAppealRequest) Reset() {
+	*x = FileAppealRequest{}
+	mi := &file_moderation_moderation_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileAppealRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileAppealRequest) ProtoMessage() {}
+
+func (x *FileAppealRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileAppealRequest.ProtoReflect.Descriptor instead.
+func (*FileAppealRequest) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *FileAppealRequest) GetReviewId() string {
+	if x != nil {
+		return x.ReviewId
+	}
+	return ""
+}
+
+func (x *FileAppealRequest) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type FileAppealResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Appeal        *Appeal                `protobuf:"bytes,1,opt,name=appeal,proto3" json:"appeal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileAppealResponse) Reset() {
+	*x = FileAppealResponse{}
+	mi := &file_moderation_moderation_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileAppealResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileAppealResponse) ProtoMessage() {}
+
+func (x *FileAppealResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileAppealResponse.ProtoReflect.Descriptor instead.
+func (*FileAppealResponse) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *FileAppealResponse) GetAppeal() *Appeal {
+	if x != nil {
+		return x.Appeal
+	}
+	return nil
+}
+
+type ListAppealsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAppealsRequest) Reset() {
+	*x = ListAppealsRequest{}
+	mi := &file_moderation_moderation_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAppealsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAppealsRequest) ProtoMessage() {}
+
+func (x *ListAppealsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAppealsRequest.ProtoReflect.Descriptor instead.
+func (*ListAppealsRequest) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListAppealsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListAppealsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListAppealsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+type ListAppealsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Appeals       []*Appeal              `protobuf:"bytes,1,rep,name=appeals,proto3" json:"appeals,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAppealsResponse) Reset() {
+	*x = ListAppealsResponse{}
+	mi := &file_moderation_moderation_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAppealsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAppealsResponse) ProtoMessage() {}
+
+func (x *ListAppealsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAppealsResponse.ProtoReflect.Descriptor instead.
+func (*ListAppealsResponse) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ListAppealsResponse) GetAppeals() []*Appeal {
+	if x != nil {
+		return x.Appeals
+	}
+	return nil
+}
+
+type ResolveAppealRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AppealId      string                 `protobuf:"bytes,1,opt,name=appeal_id,json=appealId,proto3" json:"appeal_id,omitempty"`
+	Outcome       AppealOutcome          `protobuf:"varint,2,opt,name=outcome,proto3,enum=moderation.AppealOutcome" json:"outcome,omitempty"`
+	Note          string                 `protobuf:"bytes,3,opt,name=note,proto3" json:"note,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAppealRequest) Reset() {
+	*x = ResolveAppealRequest{}
+	mi := &file_moderation_moderation_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAppealRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAppealRequest) ProtoMessage() {}
+
+func (x *ResolveAppealRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAppealRequest.ProtoReflect.Descriptor instead.
+func (*ResolveAppealRequest) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ResolveAppealRequest) GetAppealId() string {
+	if x != nil {
+		return x.AppealId
+	}
+	return ""
+}
+
+func (x *ResolveAppealRequest) GetOutcome() AppealOutcome {
+	if x != nil {
+		return x.Outcome
+	}
+	return AppealOutcome_APPEAL_OUTCOME_UNSPECIFIED
+}
+
+func (x *ResolveAppealRequest) GetNote() string {
+	if x != nil {
+		return x.Note
+	}
+	return ""
+}
+
+type ResolveAppealResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Appeal        *Appeal                `protobuf:"bytes,1,opt,name=appeal,proto3" json:"appeal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveAppealResponse) Reset() {
+	*x = ResolveAppealResponse{}
+	mi := &file_moderation_moderation_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveAppealResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveAppealResponse) ProtoMessage() {}
+
+func (x *ResolveAppealResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_moderation_moderation_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveAppealResponse.ProtoReflect.Descriptor instead.
+func (*ResolveAppealResponse) Descriptor() ([]byte, []int) {
+	return file_moderation_moderation_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ResolveAppealResponse) GetAppeal() *Appeal {
+	if x != nil {
+		return x.Appeal
+	}
+	return nil
+}
+
+var File_moderation_moderation_proto protoreflect.FileDescriptor
+
+const file_moderation_moderation_proto_rawDesc = "" +
+	"\n" +
+	"\x1bmoderation/moderation.proto\x12\n" +
+	"moderation\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xac\x01\n" +
+	"\tShadowBan\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06banned\x18\x02 \x01(\bR\x06banned\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1b\n" +
+	"\tbanned_by\x18\x04 \x01(\tR\bbannedBy\x129\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"^\n" +
+	"\x13SetShadowBanRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06banned\x18\x02 \x01(\bR\x06banned\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"L\n" +
+	"\x14SetShadowBanResponse\x124\n" +
+	"\n" +
+	"shadow_ban\x18\x01 \x01(\v2\x15.moderation.ShadowBanR\tshadowBan\".\n" +
+	"\x13GetShadowBanRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"L\n" +
+	"\x14GetShadowBanResponse\x124\n" +
+	"\n" +
+	"shadow_ban\x18\x01 \x01(\v2\x15.moderation.ShadowBanR\tshadowBan\"\x8d\x02\n" +
+	"\x0fModeratedReview\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x17\n" +
+	"\agame_id\x18\x03 \x01(\tR\x06gameId\x12\x16\n" +
+	"\x06rating\x18\x04 \x01(\x05R\x06rating\x12\x12\n" +
+	"\x04text\x18\x05 \x01(\tR\x04text\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"L\n" +
+	"\x15ModerateReviewRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"M\n" +
+	"\x16ModerateReviewResponse\x123\n" +
+	"\x06review\x18\x01 \x01(\v2\x1b.moderation.ModeratedReviewR\x06review\"\xc8\x02\n" +
+	"\x12ModerationLogEntry\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x19\n" +
+	"\bactor_id\x18\x02 \x01(\tR\aactorId\x12\x16\n" +
+	"\x06action\x18\x03 \x01(\tR\x06action\x12\x1f\n" +
+	"\vtarget_type\x18\x04 \x01(\tR\n" +
+	"targetType\x12\x1b\n" +
+	"\ttarget_id\x18\x05 \x01(\tR\btargetId\x12\x16\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12/\n" +
+	"\x06before\x18\a \x01(\v2\x17.google.protobuf.StructR\x06before\x12-\n" +
+	"\x05after\x18\b \x01(\v2\x17.google.protobuf.StructR\x05after\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x95\x02\n" +
+	"\x18ListModerationLogRequest\x12\x19\n" +
+	"\bactor_id\x18\x01 \x01(\tR\aactorId\x12\x1b\n" +
+	"\ttarget_id\x18\x02 \x01(\tR\btargetId\x12\x1f\n" +
+	"\vtarget_type\x18\x03 \x01(\tR\n" +
+	"targetType\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12.\n" +
+	"\x04from\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05limit\x18\a \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\b \x01(\x05R\x06offset\"U\n" +
+	"\x19ListModerationLogResponse\x128\n" +
+	"\aentries\x18\x01 \x03(\v2\x1e.moderation.ModerationLogEntryR\aentries\"\xe1\x02\n" +
+	"\x06Appeal\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\treview_id\x18\x02 \x01(\tR\breviewId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"removed_by\x18\x06 \x01(\tR\tremovedBy\x12\x1f\n" +
+	"\vresolved_by\x18\a \x01(\tR\n" +
+	"resolvedBy\x12'\n" +
+	"\x0fresolution_note\x18\b \x01(\tR\x0eresolutionNote\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12;\n" +
+	"\vresolved_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt\"J\n" +
+	"\x11FileAppealRequest\x12\x1b\n" +
+	"\treview_id\x18\x01 \x01(\tR\breviewId\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"@\n" +
+	"\x12FileAppealResponse\x12*\n" +
+	"\x06appeal\x18\x01 \x01(\v2\x12.moderation.AppealR\x06appeal\"Z\n" +
+	"\x12ListAppealsRequest\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\"C\n" +
+	"\x13ListAppealsResponse\x12,\n" +
+	"\aappeals\x18\x01 \x03(\v2\x12.moderation.AppealR\aappeals\"|\n" +
+	"\x14ResolveAppealRequest\x12\x1b\n" +
+	"\tappeal_id\x18\x01 \x01(\tR\bappealId\x123\n" +
+	"\aoutcome\x18\x02 \x01(\x0e2\x19.moderation.AppealOutcomeR\aoutcome\x12\x12\n" +
+	"\x04note\x18\x03 \x01(\tR\x04note\"C\n" +
+	"\x15ResolveAppealResponse\x12*\n" +
+	"\x06appeal\x18\x01 \x01(\v2\x12.moderation.AppealR\x06appeal*i\n" +
+	"\rAppealOutcome\x12\x1e\n" +
+	"\x1aAPPEAL_OUTCOME_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15APPEAL_OUTCOME_UPHELD\x10\x01\x12\x1d\n" +
+	"\x19APPEAL_OUTCOME_REINSTATED\x10\x022\x92\x06\n" +
+	"\x11ModerationService\x12Q\n" +
+	"\fSetShadowBan\x12\x1f.moderation.SetShadowBanRequest\x1a .moderation.SetShadowBanResponse\x12Q\n" +
+	"\fGetShadowBan\x12\x1f.moderation.GetShadowBanRequest\x1a .moderation.GetShadowBanResponse\x12S\n" +
+	"\n" +
+	"HideReview\x12!.moderation.ModerateReviewRequest\x1a\".moderation.ModerateReviewResponse\x12U\n" +
+	"\fDeleteReview\x12!.moderation.ModerateReviewRequest\x1a\".moderation.ModerateReviewResponse\x12V\n" +
+	"\rRestoreReview\x12!.moderation.ModerateReviewRequest\x1a\".moderation.ModerateReviewResponse\x12`\n" +
+	"\x11ListModerationLog\x12$.moderation.ListModerationLogRequest\x1a%.moderation.ListModerationLogResponse\x12K\n" +
+	"\n" +
+	"FileAppeal\x12\x1d.moderation.FileAppealRequest\x1a\x1e.moderation.FileAppealResponse\x12N\n" +
+	"\vListAppeals\x12\x1e.moderation.ListAppealsRequest\x1a\x1f.moderation.ListAppealsResponse\x12T\n" +
+	"\rResolveAppeal\x12 .moderation.ResolveAppealRequest\x1a!.moderation.ResolveAppealResponseB\"Z social-service/gen/go/moderationb\x06proto3"
+
+var (
+	file_moderation_moderation_proto_rawDescOnce sync.Once
+	file_moderation_moderation_proto_rawDescData []byte
+)
+
+func file_moderation_moderation_proto_rawDescGZIP() []byte {
+	file_moderation_moderation_proto_rawDescOnce.Do(func() {
+		file_moderation_moderation_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_moderation_moderation_proto_rawDesc), len(file_moderation_moderation_proto_rawDesc)))
+	})
+	return file_moderation_moderation_proto_rawDescData
+}
+
+var file_moderation_moderation_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_moderation_moderation_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_moderation_moderation_proto_goTypes = []any{
+	(AppealOutcome)(0),                // 0: moderation.AppealOutcome
+	(*ShadowBan)(nil),                 // 1: moderation.ShadowBan
+	(*SetShadowBanRequest)(nil),       // 2: moderation.SetShadowBanRequest
+	(*SetShadowBanResponse)(nil),      // 3: moderation.SetShadowBanResponse
+	(*GetShadowBanRequest)(nil),       // 4: moderation.GetShadowBanRequest
+	(*GetShadowBanResponse)(nil),      // 5: moderation.GetShadowBanResponse
+	(*ModeratedReview)(nil),           // 6: moderation.ModeratedReview
+	(*ModerateReviewRequest)(nil),     // 7: moderation.ModerateReviewRequest
+	(*ModerateReviewResponse)(nil),    // 8: moderation.ModerateReviewResponse
+	(*ModerationLogEntry)(nil),        // 9: moderation.ModerationLogEntry
+	(*ListModerationLogRequest)(nil),  // 10: moderation.ListModerationLogRequest
+	(*ListModerationLogResponse)(nil), // 11: moderation.ListModerationLogResponse
+	(*Appeal)(nil),                    // 12: moderation.Appeal
+	(*FileAppealRequest)(nil),         // 13: moderation.FileAppealRequest
+	(*FileAppealResponse)(nil),        // 14: moderation.FileAppealResponse
+	(*ListAppealsRequest)(nil),        // 15: moderation.ListAppealsRequest
+	(*ListAppealsResponse)(nil),       // 16: moderation.ListAppealsResponse
+	(*ResolveAppealRequest)(nil),      // 17: moderation.ResolveAppealRequest
+	(*ResolveAppealResponse)(nil),     // 18: moderation.ResolveAppealResponse
+	(*timestamppb.Timestamp)(nil),     // 19: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),           // 20: google.protobuf.Struct
+}
+var file_moderation_moderation_proto_depIdxs = []int32{
+	19, // 0: moderation.ShadowBan.created_at:type_name -> google.protobuf.Timestamp
+	1,  // 1: moderation.SetShadowBanResponse.shadow_ban:type_name -> moderation.ShadowBan
+	1,  // 2: moderation.GetShadowBanResponse.shadow_ban:type_name -> moderation.ShadowBan
+	19, // 3: moderation.ModeratedReview.created_at:type_name -> google.protobuf.Timestamp
+	19, // 4: moderation.ModeratedReview.updated_at:type_name -> google.protobuf.Timestamp
+	6,  // 5: moderation.ModerateReviewResponse.review:type_name -> moderation.ModeratedReview
+	20, // 6: moderation.ModerationLogEntry.before:type_name -> google.protobuf.Struct
+	20, // 7: moderation.ModerationLogEntry.after:type_name -> google.protobuf.Struct
+	19, // 8: moderation.ModerationLogEntry.created_at:type_name -> google.protobuf.Timestamp
+	19, // 9: moderation.ListModerationLogRequest.from:type_name -> google.protobuf.Timestamp
+	19, // 10: moderation.ListModerationLogRequest.to:type_name -> google.protobuf.Timestamp
+	9,  // 11: moderation.ListModerationLogResponse.entries:type_name -> moderation.ModerationLogEntry
+	19, // 12: moderation.Appeal.created_at:type_name -> google.protobuf.Timestamp
+	19, // 13: moderation.Appeal.resolved_at:type_name -> google.protobuf.Timestamp
+	12, // 14: moderation.FileAppealResponse.appeal:type_name -> moderation.Appeal
+	12, // 15: moderation.ListAppealsResponse.appeals:type_name -> moderation.Appeal
+	0,  // 16: moderation.ResolveAppealRequest.outcome:type_name -> moderation.AppealOutcome
+	12, // 17: moderation.ResolveAppealResponse.appeal:type_name -> moderation.Appeal
+	2,  // 18: moderation.ModerationService.SetShadowBan:input_type -> moderation.SetShadowBanRequest
+	4,  // 19: moderation.ModerationService.GetShadowBan:input_type -> moderation.GetShadowBanRequest
+	7,  // 20: moderation.ModerationService.HideReview:input_type -> moderation.ModerateReviewRequest
+	7,  // 21: moderation.ModerationService.DeleteReview:input_type -> moderation.ModerateReviewRequest
+	7,  // 22: moderation.ModerationService.RestoreReview:input_type -> moderation.ModerateReviewRequest
+	10, // 23: moderation.ModerationService.ListModerationLog:input_type -> moderation.ListModerationLogRequest
+	13, // 24: moderation.ModerationService.FileAppeal:input_type -> moderation.FileAppealRequest
+	15, // 25: moderation.ModerationService.ListAppeals:input_type -> moderation.ListAppealsRequest
+	17, // 26: moderation.ModerationService.ResolveAppeal:input_type -> moderation.ResolveAppealRequest
+	3,  // 27: moderation.ModerationService.SetShadowBan:output_type -> moderation.SetShadowBanResponse
+	5,  // 28: moderation.ModerationService.GetShadowBan:output_type -> moderation.GetShadowBanResponse
+	8,  // 29: moderation.ModerationService.HideReview:output_type -> moderation.ModerateReviewResponse
+	8,  // 30: moderation.ModerationService.DeleteReview:output_type -> moderation.ModerateReviewResponse
+	8,  // 31: moderation.ModerationService.RestoreReview:output_type -> moderation.ModerateReviewResponse
+	11, // 32: moderation.ModerationService.ListModerationLog:output_type -> moderation.ListModerationLogResponse
+	14, // 33: moderation.ModerationService.FileAppeal:output_type -> moderation.FileAppealResponse
+	16, // 34: moderation.ModerationService.ListAppeals:output_type -> moderation.ListAppealsResponse
+	18, // 35: moderation.ModerationService.ResolveAppeal:output_type -> moderation.ResolveAppealResponse
+	27, // [27:36] is the sub-list for method output_type
+	18, // [18:27] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_moderation_moderation_proto_init() }
+func file_moderation_moderation_proto_init() {
+	if File_moderation_moderation_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_moderation_moderation_proto_rawDesc), len(file_moderation_moderation_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   18,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_moderation_moderation_proto_goTypes,
+		DependencyIndexes: file_moderation_moderation_proto_depIdxs,
+		EnumInfos:         file_moderation_moderation_proto_enumTypes,
+		MessageInfos:      file_moderation_moderation_proto_msgTypes,
+	}.Build()
+	File_moderation_moderation_proto = out.File
+	file_moderation_moderation_proto_goTypes = nil
+	file_moderation_moderation_proto_depIdxs = nil
+}
@@ -1,30 +1,65 @@
 package app
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"net"
+	"net/http"
+	"os/signal"
+	moderationpb "social-service/gen/go/moderation"
+	reviewspb "social-service/gen/go/reviews"
+	"social-service/internal/auth"
+	"social-service/internal/cache"
+	"social-service/internal/certs"
 	"social-service/internal/config"
+	"social-service/internal/consumer"
 	"social-service/internal/database"
 	"social-service/internal/grpc"
+	"social-service/internal/health"
+	"social-service/internal/metrics"
 	"social-service/internal/microservice"
+	"social-service/internal/middleware"
 	"social-service/internal/producer"
+	"social-service/internal/reviewbomb"
+	"social-service/internal/storage"
+	"social-service/internal/tracing"
+	"sync"
+	"syscall"
+	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+	authpb "github.com/viktoralyoshin/playhub-proto/gen/go/auth"
+	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
 )
 
-func Start(cfg *config.Config) {
+// Start serves until SIGINT or SIGTERM and then drains. If a listener stops
+// unexpectedly it drains all the same and returns that listener's error.
+func Start(cfg *config.Config) error {
+	// Lines logged through log.Ctx outside of an RPC fall back to the global
+	// logger instead of being dropped.
+	zerolog.DefaultContextLogger = &log.Logger
+
+	shutdownTracing, err := tracing.Init(context.Background(), cfg.TracingExporter, cfg.TracingSampleRatio)
+	if err != nil {
+		log.Fatal().Err(err).Str("exporter", cfg.TracingExporter).Msg("failed to initialize tracing")
+	}
+
 	db, err := database.Init(cfg)
 	if err != nil {
 		log.Fatal().Err(err).Msg("unable to connect to database")
 	}
 
-	defer func() {
-		if err := db.Close(); err != nil {
-			log.Error().Err(err).Msg("failed to close database connection")
+	if cfg.MigrateOnStart {
+		if err := database.Migrate(context.Background(), db); err != nil {
+			log.Fatal().Err(err).Msg("migration failed")
 		}
-	}()
+	}
 
-	if err := database.Migrate(db); err != nil {
-		log.Fatal().Err(err).Msg("migration failed")
+	if err := metrics.RegisterDB(db, cfg.DBName); err != nil {
+		log.Fatal().Err(err).Msg("failed to register database metrics")
 	}
 
 	addr := ":" + cfg.GRPCPort
@@ -33,23 +68,221 @@ func Start(cfg *config.Config) {
 		log.Fatal().Err(err).Str("addr", addr).Msg("failed to listen tcp")
 	}
 
-	producer := producer.NewRatingProducer(cfg.KafkaAddr, "review_events")
-	defer func() {
-		if err := producer.Close(); err != nil {
-			log.Error().Err(err).Msg("failed to close kafka producer")
+	serverCreds, serverCerts, err := certs.ServerCredentials(cfg.ServerTLS)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to load server tls certificates")
+	}
+
+	var tokens middleware.TokenVerifier
+	if cfg.AuthMode == "jwt" {
+		verifier, err := auth.NewVerifier(auth.Options{
+			JWKSFile:       cfg.AuthJWKSFile,
+			PublicKeyFiles: cfg.AuthPublicKeyFiles,
+			HMACSecret:     cfg.AuthHMACSecret,
+			Issuer:         cfg.AuthIssuer,
+			Audience:       cfg.AuthAudience,
+			Leeway:         cfg.AuthLeeway,
+		})
+		if err != nil {
+			log.Fatal().Err(err).Msg("failed to load token verification keys")
+		}
+		tokens = verifier
+	} else {
+		log.Warn().Msg("auth_mode is gateway: x-user-id is trusted as sent, the port must only be reachable through the gateway")
+	}
+
+	appealProducer := producer.NewAppealProducer(cfg.KafkaAddr, "appeal_events")
+	ratingProducer := producer.NewRatingProducer(cfg.KafkaAddr, "review_events")
+
+	conns, err := microservice.Connect(cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("failed to connect to downstream services")
+	}
+
+	// The overall status only tracks Postgres: without it nothing can be
+	// served. Individual services also report on the dependencies they call.
+	checker := health.NewChecker(cfg.HealthCheckInterval, cfg.HealthCheckTimeout)
+	checker.AddCheck("postgres", health.DBCheck(db))
+	checker.AddCheck("kafka", health.KafkaCheck(cfg.KafkaAddr))
+	checker.AddCheck("auth-service", health.ConnCheck(conns.Auth))
+	checker.AddCheck("games-service", health.ConnCheck(conns.Games))
+	checker.AddService("", "postgres")
+	checker.AddService(socialpb.SocialService_ServiceDesc.ServiceName, "postgres", "kafka", "auth-service", "games-service")
+	checker.AddService(reviewspb.ReviewService_ServiceDesc.ServiceName, "postgres", "kafka")
+	checker.AddService(moderationpb.ModerationService_ServiceDesc.ServiceName, "postgres", "kafka")
+
+	cacheOpts := cache.Options{
+		Capacity:    cfg.CacheSize,
+		TTL:         cfg.CacheTTL,
+		NegativeTTL: cfg.CacheNegativeTTL,
+	}
+	users := microservice.NewCachedUserClient(microservice.NewUserClient(authpb.NewAuthServiceClient(conns.Auth)), cacheOpts)
+	games := microservice.NewCachedGameClient(microservice.NewGameClient(gamepb.NewGameServiceClient(conns.Games)), cacheOpts)
+
+	// Each replica caches on its own, so each reads every event itself.
+	var invalidations []*consumer.InvalidationConsumer
+	if cfg.UserEventsTopic != "" {
+		invalidations = append(invalidations, consumer.NewInvalidationConsumer(
+			cfg.KafkaAddr, cfg.UserEventsTopic, "user_id", users))
+	}
+	if cfg.GameEventsTopic != "" {
+		invalidations = append(invalidations, consumer.NewInvalidationConsumer(
+			cfg.KafkaAddr, cfg.GameEventsTopic, "game_id", games))
+	}
+
+	idempotencyKeys := storage.NewIdempotencyRepo(db)
+	detector := reviewbomb.NewDetector(storage.NewReviewBombRepo(db), reviewbomb.Config{
+		Window:             cfg.ReviewBombWindow,
+		Baseline:           cfg.ReviewBombBaseline,
+		MinReviews:         cfg.ReviewBombMinReviews,
+		RateFactor:         cfg.ReviewBombRateFactor,
+		LowRating:          cfg.ReviewBombLowRating,
+		LowShare:           cfg.ReviewBombLowShare,
+		ExcludeFromSummary: cfg.ReviewBombExclude,
+	})
+
+	s, gatewayHandler := grpc.Init(cfg, grpc.Dependencies{
+		DB:             db,
+		RatingProducer: ratingProducer,
+		AppealProducer: appealProducer,
+		Health:         checker.Server(),
+		Users:          users,
+		Games:          games,
+		Credentials:    serverCreds,
+		Tokens:         tokens,
+		Idempotency:    idempotencyKeys,
+		Detector:       detector,
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go checker.Run(ctx)
+	go serverCerts.Run(ctx, cfg.CertReloadInterval)
+	conns.WatchCertificates(ctx, cfg.CertReloadInterval)
+	go idempotencyKeys.RunPurge(ctx, time.Hour)
+	go detector.Run(ctx, cfg.ReviewBombSweepInterval)
+	for _, c := range invalidations {
+		go c.Run(ctx)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	metricsServer := &http.Server{
+		Addr:              ":" + cfg.MetricsPort,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Str("port", cfg.MetricsPort).Msg("metrics server stopped unexpectedly")
 		}
 	}()
 
-	s := grpc.Init(db, producer)
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- s.Serve(lis)
+	}()
 
-	microservice.Connect(cfg)
+	var gatewayServer *http.Server
+	if cfg.HTTPPort != "" {
+		gatewayServer = &http.Server{
+			Addr:              ":" + cfg.HTTPPort,
+			Handler:           gatewayHandler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		if serverCerts != nil {
+			gatewayServer.TLSConfig = serverCerts.HTTPServerConfig()
+		}
+
+		go func() {
+			var err error
+			if gatewayServer.TLSConfig != nil {
+				err = gatewayServer.ListenAndServeTLS("", "")
+			} else {
+				err = gatewayServer.ListenAndServe()
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				serveErr <- fmt.Errorf("rest gateway: %w", err)
+			}
+		}()
+
+		log.Info().Str("port", cfg.HTTPPort).Msg("REST gateway started")
+	}
 
 	log.Info().
 		Str("port", cfg.GRPCPort).
 		Str("service", "social-service").
+		Bool("tls", serverCreds != nil).
 		Msg("gRPC server started")
 
-	if err := s.Serve(lis); err != nil {
-		log.Fatal().Err(err).Msg("gRPC server stopped unexpectedly")
+	var stopErr error
+	select {
+	case <-ctx.Done():
+		log.Info().Dur("timeout", cfg.ShutdownTimeout).Msg("shutdown signal received, draining gRPC server")
+	case stopErr = <-serveErr:
+		log.Error().Err(stopErr).Msg("server stopped unexpectedly")
+	}
+
+	// Report NOT_SERVING and keep serving for a moment, so readiness probes
+	// stop routing traffic here before the listeners close.
+	checker.Shutdown()
+	if stopErr == nil && cfg.ShutdownDelay > 0 {
+		time.Sleep(cfg.ShutdownDelay)
 	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	// Order matters: stop accepting requests and let in-flight ones finish
+	// before flushing the producers they publish to, and close the pool last.
+	// The gateway and gRPC server drain together, within the one deadline.
+	var drain sync.WaitGroup
+	if gatewayServer != nil {
+		drain.Go(func() {
+			if err := gatewayServer.Shutdown(shutdownCtx); err != nil {
+				log.Warn().Err(err).Msg("REST gateway did not drain in time")
+			}
+		})
+	}
+
+	if !grpc.GracefulStop(shutdownCtx, s) {
+		log.Warn().Msg("shutdown deadline exceeded, in-flight RPCs were cancelled")
+	}
+	drain.Wait()
+
+	if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("failed to stop metrics server")
+	}
+
+	if err := ratingProducer.Close(); err != nil {
+		log.Error().Err(err).Msg("failed to close kafka producer")
+	}
+
+	if err := appealProducer.Close(); err != nil {
+		log.Error().Err(err).Msg("failed to close kafka appeal producer")
+	}
+
+	for _, c := range invalidations {
+		if err := c.Close(); err != nil {
+			log.Error().Err(err).Msg("failed to close cache invalidation consumer")
+		}
+	}
+
+	if err := conns.Close(); err != nil {
+		log.Error().Err(err).Msg("failed to close downstream connections")
+	}
+
+	if err := db.Close(); err != nil {
+		log.Error().Err(err).Msg("failed to close database connection")
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error().Err(err).Msg("failed to flush traces")
+	}
+
+	log.Info().Msg("social-service stopped")
+
+	return stopErr
 }
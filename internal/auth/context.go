@@ -0,0 +1,17 @@
+package auth
+
+import "context"
+
+type userKey struct{}
+
+// WithUser records the user a verified token identifies.
+func WithUser(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userKey{}, userID)
+}
+
+// UserFromContext returns the user set by WithUser. Unlike the x-user-id
+// header, it cannot be supplied by the caller.
+func UserFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userKey{}).(string)
+	return userID, ok && userID != ""
+}
@@ -0,0 +1,177 @@
+// Package auth verifies the bearer tokens issued by auth-service.
+package auth
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims mirror the access tokens minted by auth-service. Tokens from other
+// issuers may carry the user in the standard sub claim instead.
+type Claims struct {
+	jwt.RegisteredClaims
+	UserID    string `json:"user_id"`
+	UserRole  string `json:"user_role"`
+	TokenType string `json:"token_type"`
+}
+
+// User returns the authenticated user ID.
+func (c *Claims) User() string {
+	if c.UserID != "" {
+		return c.UserID
+	}
+
+	return c.Subject
+}
+
+type Options struct {
+	JWKSFile       string
+	PublicKeyFiles []string
+	HMACSecret     string
+
+	Issuer   string
+	Audience string
+	Leeway   time.Duration
+}
+
+// Verifier checks token signatures against a fixed key set along with the
+// expiry, issuer and audience claims.
+type Verifier struct {
+	byID   map[string]any
+	keys   []jwt.VerificationKey
+	parser *jwt.Parser
+}
+
+func NewVerifier(opts Options) (*Verifier, error) {
+	v := &Verifier{byID: make(map[string]any)}
+
+	if opts.JWKSFile != "" {
+		data, err := os.ReadFile(opts.JWKSFile)
+		if err != nil {
+			return nil, fmt.Errorf("jwks: %w", err)
+		}
+
+		keys, err := parseJWKS(data)
+		if err != nil {
+			return nil, fmt.Errorf("jwks %s: %w", opts.JWKSFile, err)
+		}
+
+		for _, key := range keys {
+			if key.id != "" {
+				v.byID[key.id] = key.key
+			}
+			v.keys = append(v.keys, key.key)
+		}
+	}
+
+	for _, path := range opts.PublicKeyFiles {
+		key, err := loadPublicKey(path)
+		if err != nil {
+			return nil, err
+		}
+		v.keys = append(v.keys, key)
+	}
+
+	if opts.HMACSecret != "" {
+		v.keys = append(v.keys, []byte(opts.HMACSecret))
+	}
+
+	if len(v.keys) == 0 {
+		return nil, errors.New("no verification keys configured")
+	}
+
+	parserOpts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{
+			"HS256", "HS384", "HS512",
+			"RS256", "RS384", "RS512",
+			"PS256", "PS384", "PS512",
+			"ES256", "ES384", "ES512",
+			"EdDSA",
+		}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(opts.Leeway),
+	}
+	if opts.Issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.Issuer))
+	}
+	if opts.Audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.Audience))
+	}
+
+	v.parser = jwt.NewParser(parserOpts...)
+
+	return v, nil
+}
+
+// Verify parses token and returns its claims if the signature and every
+// configured check pass. Refresh tokens are rejected.
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	claims := &Claims{}
+
+	if _, err := v.parser.ParseWithClaims(token, claims, v.keyFunc); err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != "" && claims.TokenType != "access" {
+		return nil, fmt.Errorf("unexpected token type %q", claims.TokenType)
+	}
+
+	if claims.User() == "" {
+		return nil, errors.New("token has no user_id or sub claim")
+	}
+
+	return claims, nil
+}
+
+// keyFunc selects the key named by the kid header, or offers every key when
+// the token names none. The jwt package refuses keys whose type does not
+// match the signing method, so an HMAC secret never verifies an RS256 token
+// or the other way round.
+func (v *Verifier) keyFunc(token *jwt.Token) (any, error) {
+	if kid, ok := token.Header["kid"].(string); ok && kid != "" {
+		key, found := v.byID[kid]
+		if !found {
+			return nil, fmt.Errorf("unknown key id %q", kid)
+		}
+		return key, nil
+	}
+
+	return jwt.VerificationKeySet{Keys: v.keys}, nil
+}
+
+// loadPublicKey reads a PEM public key or certificate.
+func loadPublicKey(path string) (any, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("public key %s: no PEM block found", path)
+	}
+
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("public key %s: %w", path, err)
+		}
+		return key, nil
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("public key %s: %w", path, err)
+		}
+		return cert.PublicKey, nil
+	default:
+		return nil, fmt.Errorf("public key %s: unsupported PEM block %q", path, block.Type)
+	}
+}
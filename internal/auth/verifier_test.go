@@ -0,0 +1,171 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, name string, data []byte) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+
+	return path
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func validClaims() *Claims {
+	return &Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "auth-service",
+			Audience:  jwt.ClaimStrings{"social-service"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+		UserID:    "user-1",
+		UserRole:  "user",
+		TokenType: "access",
+	}
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, kid string, key any, claims *Claims) string {
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+
+	return signed
+}
+
+func TestVerifier_HMAC(t *testing.T) {
+	v, err := NewVerifier(Options{HMACSecret: "secret", Issuer: "auth-service", Audience: "social-service"})
+	require.NoError(t, err)
+
+	claims, err := v.Verify(sign(t, jwt.SigningMethodHS256, "", []byte("secret"), validClaims()))
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", claims.User())
+	assert.Equal(t, "user", claims.UserRole)
+
+	tests := []struct {
+		name   string
+		mutate func(c *Claims)
+		key    string
+	}{
+		{name: "wrong secret", key: "other"},
+		{name: "expired", mutate: func(c *Claims) { c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour)) }},
+		{name: "no expiry", mutate: func(c *Claims) { c.ExpiresAt = nil }},
+		{name: "wrong issuer", mutate: func(c *Claims) { c.Issuer = "someone-else" }},
+		{name: "wrong audience", mutate: func(c *Claims) { c.Audience = jwt.ClaimStrings{"games-service"} }},
+		{name: "refresh token", mutate: func(c *Claims) { c.TokenType = "refresh" }},
+		{name: "no user", mutate: func(c *Claims) { c.UserID = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := validClaims()
+			if tt.mutate != nil {
+				tt.mutate(claims)
+			}
+			key := "secret"
+			if tt.key != "" {
+				key = tt.key
+			}
+
+			_, err := v.Verify(sign(t, jwt.SigningMethodHS256, "", []byte(key), claims))
+			assert.Error(t, err)
+		})
+	}
+
+	t.Run("unsigned", func(t *testing.T) {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+		require.NoError(t, err)
+
+		_, err = v.Verify(token)
+		assert.Error(t, err)
+	})
+}
+
+func TestVerifier_JWKS(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	ecPoint, err := ecKey.PublicKey.Bytes()
+	require.NoError(t, err)
+
+	jwks, err := json.Marshal(map[string]any{"keys": []map[string]string{
+		{"kty": "RSA", "kid": "rsa-1", "use": "sig", "n": b64(rsaKey.N.Bytes()), "e": b64(big.NewInt(int64(rsaKey.E)).Bytes())},
+		{"kty": "EC", "kid": "ec-1", "crv": "P-256", "x": b64(ecPoint[1:33]), "y": b64(ecPoint[33:])},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc", "n": "ignored", "e": "ignored"},
+	}})
+	require.NoError(t, err)
+
+	v, err := NewVerifier(Options{JWKSFile: writeFile(t, "jwks.json", jwks)})
+	require.NoError(t, err)
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims()))
+	assert.NoError(t, err)
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodES256, "ec-1", ecKey, validClaims()))
+	assert.NoError(t, err)
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodES256, "", ecKey, validClaims()))
+	assert.NoError(t, err, "tokens without kid are tried against every key")
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodRS256, "ec-1", rsaKey, validClaims()))
+	assert.Error(t, err, "kid must name the signing key")
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodRS256, "rotated-away", rsaKey, validClaims()))
+	assert.ErrorContains(t, err, "unknown key id")
+}
+
+func TestVerifier_PublicKeyFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	path := writeFile(t, "key.pem", pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+
+	v, err := NewVerifier(Options{PublicKeyFiles: []string{path}})
+	require.NoError(t, err)
+
+	_, err = v.Verify(sign(t, jwt.SigningMethodRS256, "", key, validClaims()))
+	assert.NoError(t, err)
+
+	// The public key must not double as an HMAC secret.
+	_, err = v.Verify(sign(t, jwt.SigningMethodHS256, "", der, validClaims()))
+	assert.Error(t, err)
+}
+
+func TestNewVerifier_Errors(t *testing.T) {
+	_, err := NewVerifier(Options{})
+	assert.ErrorContains(t, err, "no verification keys")
+
+	_, err = NewVerifier(Options{JWKSFile: writeFile(t, "jwks.json", []byte(`{"keys":[{"kty":"EC","crv":"P-999"}]}`))})
+	assert.ErrorContains(t, err, "unsupported curve")
+
+	_, err = NewVerifier(Options{PublicKeyFiles: []string{writeFile(t, "key.pem", []byte("not pem"))}})
+	assert.ErrorContains(t, err, "no PEM block")
+}
@@ -0,0 +1,176 @@
+// Package cache memoizes lookups against other services.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"social-service/internal/metrics"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Options size a Cache. Errors for which Negative returns true, such as a
+// NotFound answer, are cached for NegativeTTL; other errors are not cached.
+type Options struct {
+	Capacity    int
+	TTL         time.Duration
+	NegativeTTL time.Duration
+	Negative    func(err error) bool
+}
+
+type entry[V any] struct {
+	key     string
+	value   V
+	err     error
+	expires time.Time
+}
+
+// Cache is a size-bounded LRU whose entries also expire after a TTL.
+// Concurrent misses for the same key share a single load. Cached values are
+// shared between callers and must not be modified.
+type Cache[V any] struct {
+	name string
+	opts Options
+	now  func() time.Time
+
+	mu    sync.Mutex
+	order *list.List
+	items map[string]*list.Element
+	// epoch counts invalidations, so a load that raced with one does not
+	// store what may be the stale value.
+	epoch uint64
+
+	group singleflight.Group
+}
+
+// New returns a cache; name labels its metrics.
+func New[V any](name string, opts Options) *Cache[V] {
+	return &Cache[V]{
+		name:  name,
+		opts:  opts,
+		now:   time.Now,
+		order: list.New(),
+		items: make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached result for key or calls load. The load is detached
+// from the caller's cancellation, since other callers may be waiting on it,
+// but Get itself returns as soon as ctx is done.
+func (c *Cache[V]) Get(ctx context.Context, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	if e, ok := c.lookup(key); ok {
+		if e.err != nil {
+			metrics.ObserveCache(c.name, "negative_hit")
+		} else {
+			metrics.ObserveCache(c.name, "hit")
+		}
+		return e.value, e.err
+	}
+
+	metrics.ObserveCache(c.name, "miss")
+
+	loadCtx := context.WithoutCancel(ctx)
+	ch := c.group.DoChan(key, func() (any, error) {
+		epoch := c.currentEpoch()
+		value, err := load(loadCtx)
+		c.store(key, value, err, epoch)
+		return value, err
+	})
+
+	select {
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	case res := <-ch:
+		value, _ := res.Val.(V)
+		return value, res.Err
+	}
+}
+
+// Invalidate drops key so the next Get loads it again.
+func (c *Cache[V]) Invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	c.group.Forget(key)
+
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+}
+
+func (c *Cache[V]) currentEpoch() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.epoch
+}
+
+// Len returns the number of entries, expired ones included.
+func (c *Cache[V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *Cache[V]) lookup(key string) (*entry[V], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := el.Value.(*entry[V])
+	if !c.now().Before(e.expires) {
+		c.remove(el)
+		return nil, false
+	}
+
+	c.order.MoveToFront(el)
+	return e, true
+}
+
+func (c *Cache[V]) store(key string, value V, err error, epoch uint64) {
+	ttl := c.opts.TTL
+	if err != nil {
+		if c.opts.Negative == nil || !c.opts.Negative(err) {
+			return
+		}
+		ttl = c.opts.NegativeTTL
+	}
+	if ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.epoch != epoch {
+		return
+	}
+
+	e := &entry[V]{key: key, value: value, err: err, expires: c.now().Add(ttl)}
+
+	if el, ok := c.items[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(e)
+
+	for c.opts.Capacity > 0 && c.order.Len() > c.opts.Capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+func (c *Cache[V]) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.items, el.Value.(*entry[V]).key)
+}
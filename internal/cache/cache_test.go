@@ -0,0 +1,161 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errNotFound = errors.New("not found")
+
+func newTestCache(capacity int) (*Cache[string], *time.Time) {
+	now := time.Now()
+	c := New[string]("test", Options{
+		Capacity:    capacity,
+		TTL:         time.Minute,
+		NegativeTTL: 10 * time.Second,
+		Negative:    func(err error) bool { return errors.Is(err, errNotFound) },
+	})
+	c.now = func() time.Time { return now }
+
+	return c, &now
+}
+
+// counting returns a loader that yields value and err and counts its calls.
+func counting(calls *atomic.Int32, value string, err error) func(context.Context) (string, error) {
+	return func(context.Context) (string, error) {
+		calls.Add(1)
+		return value, err
+	}
+}
+
+func TestCache_TTL(t *testing.T) {
+	c, now := newTestCache(10)
+	var calls atomic.Int32
+
+	for range 3 {
+		value, err := c.Get(context.Background(), "g1", counting(&calls, "game", nil))
+		require.NoError(t, err)
+		assert.Equal(t, "game", value)
+	}
+	assert.EqualValues(t, 1, calls.Load())
+
+	*now = now.Add(time.Minute)
+	_, _ = c.Get(context.Background(), "g1", counting(&calls, "game", nil))
+	assert.EqualValues(t, 2, calls.Load(), "expired entries are loaded again")
+}
+
+func TestCache_Negative(t *testing.T) {
+	c, now := newTestCache(10)
+	var calls atomic.Int32
+
+	for range 2 {
+		_, err := c.Get(context.Background(), "missing", counting(&calls, "", errNotFound))
+		assert.ErrorIs(t, err, errNotFound)
+	}
+	assert.EqualValues(t, 1, calls.Load())
+
+	*now = now.Add(10 * time.Second)
+	_, _ = c.Get(context.Background(), "missing", counting(&calls, "", errNotFound))
+	assert.EqualValues(t, 2, calls.Load(), "negative entries use the shorter TTL")
+
+	calls.Store(0)
+	for range 2 {
+		_, err := c.Get(context.Background(), "flaky", counting(&calls, "", errors.New("unavailable")))
+		assert.Error(t, err)
+	}
+	assert.EqualValues(t, 2, calls.Load(), "other errors are not cached")
+}
+
+func TestCache_LRU(t *testing.T) {
+	c, _ := newTestCache(2)
+	var calls atomic.Int32
+
+	_, _ = c.Get(context.Background(), "a", counting(&calls, "a", nil))
+	_, _ = c.Get(context.Background(), "b", counting(&calls, "b", nil))
+	_, _ = c.Get(context.Background(), "a", counting(&calls, "a", nil))
+	_, _ = c.Get(context.Background(), "c", counting(&calls, "c", nil))
+
+	assert.Equal(t, 2, c.Len())
+	assert.EqualValues(t, 3, calls.Load())
+
+	_, _ = c.Get(context.Background(), "a", counting(&calls, "a", nil))
+	assert.EqualValues(t, 3, calls.Load(), "recently used entries survive")
+
+	_, _ = c.Get(context.Background(), "b", counting(&calls, "b", nil))
+	assert.EqualValues(t, 4, calls.Load(), "the least recently used entry was evicted")
+}
+
+func TestCache_Singleflight(t *testing.T) {
+	c, _ := newTestCache(10)
+
+	var calls atomic.Int32
+	release := make(chan struct{})
+	load := func(context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "game", nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := c.Get(context.Background(), "g1", load)
+			assert.NoError(t, err)
+			assert.Equal(t, "game", value)
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, calls.Load())
+}
+
+func TestCache_CallerCancelled(t *testing.T) {
+	c, _ := newTestCache(10)
+
+	release := make(chan struct{})
+	defer close(release)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := c.Get(ctx, "g1", func(context.Context) (string, error) {
+		<-release
+		return "game", nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCache_Invalidate(t *testing.T) {
+	c, _ := newTestCache(10)
+	var calls atomic.Int32
+
+	_, _ = c.Get(context.Background(), "g1", counting(&calls, "old", nil))
+	c.Invalidate("g1")
+
+	value, _ := c.Get(context.Background(), "g1", counting(&calls, "new", nil))
+	assert.Equal(t, "new", value)
+	assert.EqualValues(t, 2, calls.Load())
+
+	t.Run("during load", func(t *testing.T) {
+		value, _ := c.Get(context.Background(), "g2", func(context.Context) (string, error) {
+			c.Invalidate("g2")
+			return "stale", nil
+		})
+		assert.Equal(t, "stale", value)
+
+		value, _ = c.Get(context.Background(), "g2", counting(&calls, "fresh", nil))
+		assert.Equal(t, "fresh", value, "a load that raced with an invalidation is not cached")
+	})
+}
@@ -0,0 +1,222 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"social-service/internal/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+type authority struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newAuthority(t *testing.T, name string) *authority {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &authority{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue signs a leaf certificate for name and returns it and its key as PEM.
+func (a *authority) issue(t *testing.T, name string, serial int64) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: name},
+		DNSNames:     []string{name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, a.cert, &key.PublicKey, a.key)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// writeTLS writes a key pair and CA bundle into dir and returns the config
+// pointing at them.
+func writeTLS(t *testing.T, dir string, certPEM, keyPEM, caPEM []byte) config.TLS {
+	cfg := config.TLS{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "tls.crt"),
+		KeyFile:  filepath.Join(dir, "tls.key"),
+		CAFile:   filepath.Join(dir, "ca.crt"),
+	}
+
+	require.NoError(t, os.WriteFile(cfg.CertFile, certPEM, 0o600))
+	require.NoError(t, os.WriteFile(cfg.KeyFile, keyPEM, 0o600))
+	require.NoError(t, os.WriteFile(cfg.CAFile, caPEM, 0o600))
+
+	return cfg
+}
+
+// serveHealth starts a TLS gRPC server and returns its address.
+func serveHealth(t *testing.T, cfg config.TLS) string {
+	creds, _, err := ServerCredentials(cfg)
+	require.NoError(t, err)
+
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	s := grpc.NewServer(grpc.Creds(creds))
+	healthpb.RegisterHealthServer(s, health.NewServer())
+	go func() { _ = s.Serve(lis) }()
+	t.Cleanup(s.Stop)
+
+	return lis.Addr().String()
+}
+
+func check(t *testing.T, addr string, cfg config.TLS) error {
+	creds, _, err := ClientCredentials(cfg)
+	require.NoError(t, err)
+
+	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(creds))
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	_, err = healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	return err
+}
+
+func TestMutualTLS(t *testing.T) {
+	ca := newAuthority(t, "test-ca")
+
+	serverCert, serverKey := ca.issue(t, "social.internal", 2)
+	serverCfg := writeTLS(t, t.TempDir(), serverCert, serverKey, ca.pem)
+	addr := serveHealth(t, serverCfg)
+
+	clientCert, clientKey := ca.issue(t, "games.internal", 3)
+	clientCfg := writeTLS(t, t.TempDir(), clientCert, clientKey, ca.pem)
+	clientCfg.ServerName = "social.internal"
+
+	t.Run("trusted client", func(t *testing.T) {
+		assert.NoError(t, check(t, addr, clientCfg))
+	})
+
+	t.Run("client without certificate", func(t *testing.T) {
+		cfg := clientCfg
+		cfg.CertFile, cfg.KeyFile = "", ""
+		assert.Error(t, check(t, addr, cfg))
+	})
+
+	t.Run("wrong server name", func(t *testing.T) {
+		cfg := clientCfg
+		cfg.ServerName = "other.internal"
+		assert.Error(t, check(t, addr, cfg))
+	})
+
+	t.Run("untrusted server", func(t *testing.T) {
+		other := newAuthority(t, "other-ca")
+		cfg := clientCfg
+		cfg.CAFile = filepath.Join(t.TempDir(), "ca.crt")
+		require.NoError(t, os.WriteFile(cfg.CAFile, other.pem, 0o600))
+		assert.Error(t, check(t, addr, cfg))
+	})
+}
+
+func TestReloader_Rotation(t *testing.T) {
+	first := newAuthority(t, "first-ca")
+	cert, key := first.issue(t, "social.internal", 2)
+	cfg := writeTLS(t, t.TempDir(), cert, key, first.pem)
+
+	r, err := NewReloader(cfg)
+	require.NoError(t, err)
+
+	reloaded, err := r.reload()
+	require.NoError(t, err)
+	assert.False(t, reloaded, "unchanged files are not reloaded")
+
+	second := newAuthority(t, "second-ca")
+	cert, key = second.issue(t, "social.internal", 3)
+
+	// A certificate without its matching key fails to load and leaves the
+	// previous pair in place.
+	require.NoError(t, os.WriteFile(cfg.CertFile, cert, 0o600))
+	_, err = r.reload()
+	require.Error(t, err)
+
+	current, _ := r.current()
+	leaf, err := x509.ParseCertificate(current.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "first-ca", leaf.Issuer.CommonName)
+
+	require.NoError(t, os.WriteFile(cfg.KeyFile, key, 0o600))
+	require.NoError(t, os.WriteFile(cfg.CAFile, second.pem, 0o600))
+
+	reloaded, err = r.reload()
+	require.NoError(t, err)
+	assert.True(t, reloaded)
+
+	current, pool := r.current()
+	leaf, err = x509.ParseCertificate(current.Certificate[0])
+	require.NoError(t, err)
+	assert.Equal(t, "second-ca", leaf.Issuer.CommonName)
+
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: pool, DNSName: "social.internal"})
+	assert.NoError(t, err)
+}
+
+func TestCredentials_Disabled(t *testing.T) {
+	creds, r, err := ServerCredentials(config.TLS{})
+	assert.NoError(t, err)
+	assert.Nil(t, creds)
+	assert.Nil(t, r)
+
+	creds, r, err = ClientCredentials(config.TLS{})
+	assert.NoError(t, err)
+	assert.Equal(t, "insecure", creds.Info().SecurityProtocol)
+	assert.Nil(t, r)
+
+	// Run on a disabled reloader returns instead of blocking.
+	r.Run(context.Background(), time.Second)
+
+	_, _, err = ServerCredentials(config.TLS{Enabled: true})
+	assert.ErrorContains(t, err, "cert_file and key_file")
+}
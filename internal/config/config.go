@@ -1,31 +1,792 @@
 package config
 
-import "os"
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "[REDACTED]"
+
+type RateLimit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+// TLS configures one side of a gRPC connection. For the server, ca_file
+// turns on client-certificate verification; for a downstream client it
+// replaces the system roots, and cert_file/key_file enable mutual TLS.
+type TLS struct {
+	Enabled  bool   `yaml:"enabled"`
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+	CAFile   string `yaml:"ca_file"`
+
+	// ServerName overrides the name verified in the server certificate;
+	// clients only.
+	ServerName string `yaml:"server_name"`
+}
 
 type Config struct {
-	DBUser          string
-	DBHost          string
-	DBPassword      string
-	DBPort          string
-	DBName          string
-	GRPCPort        string
-	GameServiceAddr string
-	AuthServiceAddr string
-	KafkaAddr       string
-	Env             string
-}
-
-func Load() *Config {
+	DBUser          string `yaml:"db_user"`
+	DBHost          string `yaml:"db_host"`
+	DBPassword      string `yaml:"db_password"`
+	DBPort          string `yaml:"db_port"`
+	DBName          string `yaml:"db_name"`
+	GRPCPort        string `yaml:"grpc_port"`
+	HTTPPort        string `yaml:"http_port"`
+	MetricsPort     string `yaml:"metrics_port"`
+	GameServiceAddr string `yaml:"game_service_addr"`
+	AuthServiceAddr string `yaml:"auth_service_addr"`
+	KafkaAddr       string `yaml:"kafka_addr"`
+	Env             string `yaml:"env"`
+
+	// DBPasswordFile, when set, replaces DBPassword with the file contents,
+	// e.g. a mounted Kubernetes secret.
+	DBPasswordFile string `yaml:"db_password_file"`
+
+	// DownstreamTimeout bounds each attempt of a call to auth-service or
+	// games-service; transient failures are retried up to
+	// DownstreamMaxAttempts times with jittered exponential backoff.
+	DownstreamTimeout     time.Duration `yaml:"downstream_timeout"`
+	DownstreamMaxAttempts int           `yaml:"downstream_max_attempts"`
+	DownstreamBackoff     time.Duration `yaml:"downstream_backoff"`
+	DownstreamMaxBackoff  time.Duration `yaml:"downstream_max_backoff"`
+
+	// BreakerFailures consecutive failures open a downstream's circuit
+	// breaker for BreakerCooldown.
+	BreakerFailures int           `yaml:"breaker_failures"`
+	BreakerCooldown time.Duration `yaml:"breaker_cooldown"`
+
+	// User and game existence checks are cached for CacheTTL, or
+	// CacheNegativeTTL when the entity does not exist, in an LRU of
+	// CacheSize entries per downstream.
+	CacheSize        int           `yaml:"cache_size"`
+	CacheTTL         time.Duration `yaml:"cache_ttl"`
+	CacheNegativeTTL time.Duration `yaml:"cache_negative_ttl"`
+
+	// UserEventsTopic and GameEventsTopic, when set, are consumed to evict
+	// changed users and games from the cache.
+	UserEventsTopic string `yaml:"user_events_topic"`
+	GameEventsTopic string `yaml:"game_events_topic"`
+
+	ServerTLS      TLS `yaml:"server_tls"`
+	GameServiceTLS TLS `yaml:"game_service_tls"`
+	AuthServiceTLS TLS `yaml:"auth_service_tls"`
+
+	// AuthMode is "gateway", trusting the x-user-id and x-user-role headers
+	// set by an upstream gateway, or "jwt", deriving them from a verified
+	// bearer token.
+	AuthMode string `yaml:"auth_mode"`
+
+	// Keys that may sign tokens: a JWKS document, PEM public keys and a
+	// shared HMAC secret. Any combination is accepted.
+	AuthJWKSFile       string   `yaml:"auth_jwks_file"`
+	AuthPublicKeyFiles []string `yaml:"auth_public_key_files"`
+	AuthHMACSecret     string   `yaml:"auth_hmac_secret"`
+	AuthHMACSecretFile string   `yaml:"auth_hmac_secret_file"`
+
+	// AuthIssuer and AuthAudience, when set, must match the token claims.
+	AuthIssuer   string        `yaml:"auth_issuer"`
+	AuthAudience string        `yaml:"auth_audience"`
+	AuthLeeway   time.Duration `yaml:"auth_leeway"`
+
+	// CertReloadInterval is how often TLS files are checked for rotation.
+	CertReloadInterval time.Duration `yaml:"cert_reload_interval"`
+
+	// MigrateOnStart applies pending migrations before serving. Deployments
+	// that run "migrate up" as a separate job turn it off.
+	MigrateOnStart bool `yaml:"migrate_on_start"`
+
+	// ShutdownTimeout bounds how long in-flight RPCs may run after SIGTERM
+	// before the server stops them forcibly.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+
+	// ShutdownDelay is how long the server keeps serving after reporting
+	// NOT_SERVING on SIGTERM, so readiness probes take it out of rotation
+	// before the listener closes.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+
+	HealthCheckInterval time.Duration `yaml:"health_check_interval"`
+	HealthCheckTimeout  time.Duration `yaml:"health_check_timeout"`
+
+	// TracingExporter is one of "none", "stdout" or "otlp".
+	TracingExporter    string  `yaml:"tracing_exporter"`
+	TracingSampleRatio float64 `yaml:"tracing_sample_ratio"`
+
+	ReviewBombWindow     time.Duration `yaml:"review_bomb_window"`
+	ReviewBombBaseline   time.Duration `yaml:"review_bomb_baseline"`
+	ReviewBombMinReviews int           `yaml:"review_bomb_min_reviews"`
+	ReviewBombRateFactor float64       `yaml:"review_bomb_rate_factor"`
+	ReviewBombLowRating  int           `yaml:"review_bomb_low_rating"`
+	ReviewBombLowShare   float64       `yaml:"review_bomb_low_share"`
+	ReviewBombExclude    bool          `yaml:"review_bomb_exclude"`
+
+	// ReviewBombSweepInterval is how often games with an open review-bomb
+	// period are re-evaluated, so periods close once reviews stop.
+	ReviewBombSweepInterval time.Duration `yaml:"review_bomb_sweep_interval"`
+
+	// MaxPageSize caps the limit of listing requests and
+	// MaxReviewTextLength the characters of a review.
+	MaxPageSize         int `yaml:"max_page_size"`
+	MaxReviewTextLength int `yaml:"max_review_text_length"`
+
+	// ReviewReleasedOnly rejects reviews of games that are not out yet.
+	ReviewReleasedOnly bool `yaml:"review_released_only"`
+
+	// IdempotencyTTL is how long the response to a write sent with an
+	// idempotency-key header is kept for retries, and IdempotencyLease how
+	// long a key stays claimed by a request that has not finished.
+	IdempotencyTTL   time.Duration `yaml:"idempotency_ttl"`
+	IdempotencyLease time.Duration `yaml:"idempotency_lease"`
+
+	// FeedHistory is how many recent reviews the live feed keeps for
+	// subscribers resuming from a cursor, FeedBuffer how many may queue for
+	// one subscriber before it is dropped as too slow, and
+	// FeedMaxSubscribers caps concurrent streams.
+	FeedHistory        int `yaml:"feed_history"`
+	FeedBuffer         int `yaml:"feed_buffer"`
+	FeedMaxSubscribers int `yaml:"feed_max_subscribers"`
+
+	// RateLimits maps an RPC name, e.g. "CreateReview", to its per-caller
+	// token bucket.
+	RateLimits map[string]RateLimit `yaml:"rate_limits"`
+
+	// PrintConfig is set by --print-config: the service prints the effective
+	// configuration instead of starting.
+	PrintConfig bool `yaml:"-"`
+}
+
+func Default() *Config {
 	return &Config{
-		DBUser:          os.Getenv("DB_USER"),
-		DBName:          os.Getenv("DB_NAME"),
-		DBHost:          os.Getenv("DB_HOST"),
-		DBPassword:      os.Getenv("DB_PASSWORD"),
-		DBPort:          os.Getenv("DB_PORT"),
-		GRPCPort:        os.Getenv("GRPC_PORT"),
-		GameServiceAddr: os.Getenv("GAME_SERVICE_ADDR"),
-		AuthServiceAddr: os.Getenv("AUTH_SERVICE_ADDR"),
-		Env:             os.Getenv("ENV"),
-		KafkaAddr:       os.Getenv("KAFKA_ADDR"),
+		MetricsPort: "9090",
+
+		MigrateOnStart: true,
+
+		DownstreamTimeout:     2 * time.Second,
+		DownstreamMaxAttempts: 3,
+		DownstreamBackoff:     100 * time.Millisecond,
+		DownstreamMaxBackoff:  time.Second,
+		BreakerFailures:       5,
+		BreakerCooldown:       30 * time.Second,
+
+		MaxPageSize:         200,
+		MaxReviewTextLength: 5000,
+
+		IdempotencyTTL:   24 * time.Hour,
+		IdempotencyLease: time.Minute,
+
+		FeedHistory:        1000,
+		FeedBuffer:         64,
+		FeedMaxSubscribers: 10000,
+
+		CacheSize:        10000,
+		CacheTTL:         5 * time.Minute,
+		CacheNegativeTTL: 30 * time.Second,
+
+		CertReloadInterval: 30 * time.Second,
+
+		AuthMode:   "gateway",
+		AuthLeeway: 30 * time.Second,
+
+		ShutdownTimeout: 20 * time.Second,
+		ShutdownDelay:   5 * time.Second,
+
+		HealthCheckInterval: 5 * time.Second,
+		HealthCheckTimeout:  2 * time.Second,
+
+		TracingExporter:    "none",
+		TracingSampleRatio: 1,
+
+		ReviewBombWindow:     time.Hour,
+		ReviewBombBaseline:   7 * 24 * time.Hour,
+		ReviewBombMinReviews: 50,
+		ReviewBombRateFactor: 5,
+		ReviewBombLowRating:  20,
+		ReviewBombLowShare:   0.7,
+
+		ReviewBombSweepInterval: 5 * time.Minute,
+
+		RateLimits: map[string]RateLimit{
+			"CreateReview": {Rate: 0.5, Burst: 5},
+		},
+	}
+}
+
+// Load builds the configuration from defaults, then the YAML file given by
+// --config or CONFIG_FILE, then environment variables, then command-line
+// flags; each layer overrides the previous one. Every problem found is
+// reported in the returned error, not only the first.
+func Load(args []string) (*Config, error) {
+	return load(args, (*Config).Validate)
+}
+
+// LoadDatabase is Load for commands that only talk to Postgres, such as
+// migrate: settings of the other dependencies are not required.
+func LoadDatabase(args []string) (*Config, error) {
+	return load(args, (*Config).ValidateDatabase)
+}
+
+func load(args []string, validate func(*Config) error) (*Config, error) {
+	cfg := Default()
+
+	fs := flag.NewFlagSet("social-service", flag.ContinueOnError)
+	configFile := fs.String("config", os.Getenv("CONFIG_FILE"), "path to a YAML config file")
+	fs.BoolVar(&cfg.PrintConfig, "print-config", false, "print the effective configuration and exit")
+	for _, f := range flags {
+		fs.String(f.name, "", f.usage)
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("unexpected argument %q", fs.Arg(0))
+	}
+
+	if *configFile != "" {
+		if err := loadFile(cfg, *configFile); err != nil {
+			return nil, err
+		}
+	}
+
+	var errs []error
+
+	env := &envLoader{}
+	env.apply(cfg)
+	errs = append(errs, env.errs...)
+
+	fs.Visit(func(fl *flag.Flag) {
+		for _, f := range flags {
+			if f.name == fl.Name {
+				if err := f.set(cfg, fl.Value.String()); err != nil {
+					errs = append(errs, fmt.Errorf("flag --%s: %w", f.name, err))
+				}
+			}
+		}
+	})
+
+	if cfg.DBPasswordFile != "" {
+		secret, err := os.ReadFile(cfg.DBPasswordFile)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("db_password_file: %w", err))
+		} else {
+			cfg.DBPassword = strings.TrimRight(string(secret), "\r\n")
+		}
+	}
+
+	if cfg.AuthHMACSecretFile != "" {
+		secret, err := os.ReadFile(cfg.AuthHMACSecretFile)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("auth_hmac_secret_file: %w", err))
+		} else {
+			cfg.AuthHMACSecret = strings.TrimRight(string(secret), "\r\n")
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	if err := validate(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+func loadFile(cfg *Config, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("config file: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("config file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// ValidateDatabase reports every invalid or missing Postgres setting at once.
+func (c *Config) ValidateDatabase() error {
+	var errs []error
+
+	for _, field := range []struct{ name, value string }{
+		{"db_host", c.DBHost},
+		{"db_port", c.DBPort},
+		{"db_user", c.DBUser},
+		{"db_name", c.DBName},
+	} {
+		if field.value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", field.name))
+		}
+	}
+
+	if err := validatePort("db_port", c.DBPort); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// Validate reports every invalid or missing setting at once.
+func (c *Config) Validate() error {
+	errs := []error{c.ValidateDatabase()}
+
+	for _, field := range []struct{ name, value string }{
+		{"grpc_port", c.GRPCPort},
+		{"kafka_addr", c.KafkaAddr},
+		{"game_service_addr", c.GameServiceAddr},
+		{"auth_service_addr", c.AuthServiceAddr},
+	} {
+		if field.value == "" {
+			errs = append(errs, fmt.Errorf("%s is required", field.name))
+		}
+	}
+
+	for _, field := range []struct{ name, value string }{
+		{"grpc_port", c.GRPCPort},
+		{"http_port", c.HTTPPort},
+		{"metrics_port", c.MetricsPort},
+	} {
+		if err := validatePort(field.name, field.value); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	for _, field := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"shutdown_timeout", c.ShutdownTimeout},
+		{"health_check_interval", c.HealthCheckInterval},
+		{"health_check_timeout", c.HealthCheckTimeout},
+		{"cert_reload_interval", c.CertReloadInterval},
+		{"downstream_timeout", c.DownstreamTimeout},
+		{"downstream_backoff", c.DownstreamBackoff},
+		{"downstream_max_backoff", c.DownstreamMaxBackoff},
+		{"breaker_cooldown", c.BreakerCooldown},
+		{"cache_ttl", c.CacheTTL},
+		{"cache_negative_ttl", c.CacheNegativeTTL},
+		{"idempotency_ttl", c.IdempotencyTTL},
+		{"idempotency_lease", c.IdempotencyLease},
+		{"review_bomb_window", c.ReviewBombWindow},
+		{"review_bomb_baseline", c.ReviewBombBaseline},
+		{"review_bomb_sweep_interval", c.ReviewBombSweepInterval},
+	} {
+		if field.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", field.name, field.value))
+		}
+	}
+
+	if c.ReviewBombBaseline <= c.ReviewBombWindow {
+		errs = append(errs, errors.New("review_bomb_baseline must be longer than review_bomb_window"))
+	}
+
+	if c.IdempotencyLease > c.IdempotencyTTL {
+		errs = append(errs, errors.New("idempotency_lease must not be longer than idempotency_ttl"))
+	}
+
+	if c.ReviewBombLowShare < 0 || c.ReviewBombLowShare > 1 {
+		errs = append(errs, fmt.Errorf("review_bomb_low_share must be within [0, 1], got %v", c.ReviewBombLowShare))
+	}
+
+	if c.TracingSampleRatio < 0 || c.TracingSampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing_sample_ratio must be within [0, 1], got %v", c.TracingSampleRatio))
 	}
+
+	if c.DownstreamMaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("downstream_max_attempts must be at least 1, got %d", c.DownstreamMaxAttempts))
+	}
+
+	if c.BreakerFailures < 1 {
+		errs = append(errs, fmt.Errorf("breaker_failures must be at least 1, got %d", c.BreakerFailures))
+	}
+
+	if c.MaxPageSize < 1 {
+		errs = append(errs, fmt.Errorf("max_page_size must be at least 1, got %d", c.MaxPageSize))
+	}
+
+	if c.MaxReviewTextLength < 1 {
+		errs = append(errs, fmt.Errorf("max_review_text_length must be at least 1, got %d", c.MaxReviewTextLength))
+	}
+
+	for _, field := range []struct {
+		name  string
+		value int
+	}{
+		{"feed_history", c.FeedHistory},
+		{"feed_buffer", c.FeedBuffer},
+		{"feed_max_subscribers", c.FeedMaxSubscribers},
+	} {
+		if field.value < 1 {
+			errs = append(errs, fmt.Errorf("%s must be at least 1, got %d", field.name, field.value))
+		}
+	}
+
+	if c.CacheSize < 1 {
+		errs = append(errs, fmt.Errorf("cache_size must be at least 1, got %d", c.CacheSize))
+	}
+
+	if c.ServerTLS.Enabled && (c.ServerTLS.CertFile == "" || c.ServerTLS.KeyFile == "") {
+		errs = append(errs, errors.New("server_tls needs cert_file and key_file when enabled"))
+	}
+
+	for _, field := range []struct {
+		name  string
+		value TLS
+	}{
+		{"server_tls", c.ServerTLS},
+		{"game_service_tls", c.GameServiceTLS},
+		{"auth_service_tls", c.AuthServiceTLS},
+	} {
+		if (field.value.CertFile == "") != (field.value.KeyFile == "") {
+			errs = append(errs, fmt.Errorf("%s.cert_file and %s.key_file must be set together", field.name, field.name))
+		}
+	}
+
+	switch c.AuthMode {
+	case "gateway":
+	case "jwt":
+		if c.AuthJWKSFile == "" && len(c.AuthPublicKeyFiles) == 0 && c.AuthHMACSecret == "" {
+			errs = append(errs, errors.New("auth_mode jwt needs auth_jwks_file, auth_public_key_files or auth_hmac_secret"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("auth_mode must be one of gateway, jwt, got %q", c.AuthMode))
+	}
+
+	if c.AuthLeeway < 0 {
+		errs = append(errs, fmt.Errorf("auth_leeway must not be negative, got %s", c.AuthLeeway))
+	}
+
+	if c.ShutdownDelay < 0 {
+		errs = append(errs, fmt.Errorf("shutdown_delay must not be negative, got %s", c.ShutdownDelay))
+	}
+
+	switch c.TracingExporter {
+	case "none", "stdout", "otlp":
+	default:
+		errs = append(errs, fmt.Errorf("tracing_exporter must be one of none, stdout, otlp, got %q", c.TracingExporter))
+	}
+
+	for method, limit := range c.RateLimits {
+		if limit.Rate <= 0 || limit.Burst < 1 {
+			errs = append(errs, fmt.Errorf("rate_limits.%s needs a positive rate and burst", method))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validatePort accepts an empty value: missing settings are reported by the
+// required check.
+func validatePort(name, value string) error {
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("%s must be a port number, got %q", name, value)
+	}
+
+	return nil
+}
+
+// Print writes the configuration as YAML with secrets redacted.
+func (c *Config) Print(w io.Writer) error {
+	printed := *c
+	if printed.DBPassword != "" {
+		printed.DBPassword = redacted
+	}
+	if printed.AuthHMACSecret != "" {
+		printed.AuthHMACSecret = redacted
+	}
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+
+	if err := encoder.Encode(&printed); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+type flagDef struct {
+	name  string
+	usage string
+	set   func(cfg *Config, value string) error
+}
+
+var flags = []flagDef{
+	{"env", "environment name", setString(func(c *Config) *string { return &c.Env })},
+	{"grpc-port", "gRPC listen port", setString(func(c *Config) *string { return &c.GRPCPort })},
+	{"http-port", "REST gateway listen port, empty to disable", setString(func(c *Config) *string { return &c.HTTPPort })},
+	{"metrics-port", "metrics HTTP listen port", setString(func(c *Config) *string { return &c.MetricsPort })},
+	{"db-host", "Postgres host", setString(func(c *Config) *string { return &c.DBHost })},
+	{"db-port", "Postgres port", setString(func(c *Config) *string { return &c.DBPort })},
+	{"db-name", "Postgres database", setString(func(c *Config) *string { return &c.DBName })},
+	{"db-user", "Postgres user", setString(func(c *Config) *string { return &c.DBUser })},
+	{"db-password-file", "file holding the Postgres password", setString(func(c *Config) *string { return &c.DBPasswordFile })},
+	{"kafka-addr", "Kafka broker address", setString(func(c *Config) *string { return &c.KafkaAddr })},
+	{"game-service-addr", "games service address", setString(func(c *Config) *string { return &c.GameServiceAddr })},
+	{"auth-service-addr", "auth service address", setString(func(c *Config) *string { return &c.AuthServiceAddr })},
+	{"auth-mode", "gateway or jwt", setString(func(c *Config) *string { return &c.AuthMode })},
+	{"tracing-exporter", "none, stdout or otlp", setString(func(c *Config) *string { return &c.TracingExporter })},
+	{"migrate-on-start", "apply pending migrations before serving", func(c *Config, value string) error {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		c.MigrateOnStart = b
+		return nil
+	}},
+	{"shutdown-timeout", "graceful shutdown deadline, e.g. 20s", func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		c.ShutdownTimeout = d
+		return nil
+	}},
+	{"shutdown-delay", "time to keep serving after reporting NOT_SERVING, e.g. 5s", func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		c.ShutdownDelay = d
+		return nil
+	}},
+}
+
+func setString(field func(c *Config) *string) func(*Config, string) error {
+	return func(c *Config, value string) error {
+		*field(c) = value
+		return nil
+	}
+}
+
+// envLoader overrides config values from environment variables. Variables
+// that are set but malformed are collected in errs.
+type envLoader struct {
+	errs []error
+}
+
+func (e *envLoader) apply(cfg *Config) {
+	e.string("DB_USER", &cfg.DBUser)
+	e.string("DB_NAME", &cfg.DBName)
+	e.string("DB_HOST", &cfg.DBHost)
+	e.string("DB_PASSWORD", &cfg.DBPassword)
+	e.string("DB_PASSWORD_FILE", &cfg.DBPasswordFile)
+	e.string("DB_PORT", &cfg.DBPort)
+	e.string("GRPC_PORT", &cfg.GRPCPort)
+	e.optionalString("HTTP_PORT", &cfg.HTTPPort)
+	e.string("METRICS_PORT", &cfg.MetricsPort)
+	e.string("GAME_SERVICE_ADDR", &cfg.GameServiceAddr)
+	e.string("AUTH_SERVICE_ADDR", &cfg.AuthServiceAddr)
+	e.string("ENV", &cfg.Env)
+	e.string("KAFKA_ADDR", &cfg.KafkaAddr)
+
+	e.duration("DOWNSTREAM_TIMEOUT", &cfg.DownstreamTimeout)
+	e.int("DOWNSTREAM_MAX_ATTEMPTS", &cfg.DownstreamMaxAttempts)
+	e.duration("DOWNSTREAM_BACKOFF", &cfg.DownstreamBackoff)
+	e.duration("DOWNSTREAM_MAX_BACKOFF", &cfg.DownstreamMaxBackoff)
+	e.int("BREAKER_FAILURES", &cfg.BreakerFailures)
+	e.duration("BREAKER_COOLDOWN", &cfg.BreakerCooldown)
+
+	e.int("CACHE_SIZE", &cfg.CacheSize)
+	e.duration("CACHE_TTL", &cfg.CacheTTL)
+	e.duration("CACHE_NEGATIVE_TTL", &cfg.CacheNegativeTTL)
+	e.string("USER_EVENTS_TOPIC", &cfg.UserEventsTopic)
+	e.string("GAME_EVENTS_TOPIC", &cfg.GameEventsTopic)
+
+	e.tls("SERVER_TLS", &cfg.ServerTLS)
+	e.tls("GAME_SERVICE_TLS", &cfg.GameServiceTLS)
+	e.tls("AUTH_SERVICE_TLS", &cfg.AuthServiceTLS)
+	e.duration("CERT_RELOAD_INTERVAL", &cfg.CertReloadInterval)
+
+	e.string("AUTH_MODE", &cfg.AuthMode)
+	e.string("AUTH_JWKS_FILE", &cfg.AuthJWKSFile)
+	e.list("AUTH_PUBLIC_KEY_FILES", &cfg.AuthPublicKeyFiles)
+	e.string("AUTH_HMAC_SECRET", &cfg.AuthHMACSecret)
+	e.string("AUTH_HMAC_SECRET_FILE", &cfg.AuthHMACSecretFile)
+	e.string("AUTH_ISSUER", &cfg.AuthIssuer)
+	e.string("AUTH_AUDIENCE", &cfg.AuthAudience)
+	e.duration("AUTH_LEEWAY", &cfg.AuthLeeway)
+
+	e.bool("MIGRATE_ON_START", &cfg.MigrateOnStart)
+
+	e.duration("SHUTDOWN_TIMEOUT", &cfg.ShutdownTimeout)
+	e.duration("SHUTDOWN_DELAY", &cfg.ShutdownDelay)
+
+	e.duration("HEALTH_CHECK_INTERVAL", &cfg.HealthCheckInterval)
+	e.duration("HEALTH_CHECK_TIMEOUT", &cfg.HealthCheckTimeout)
+
+	e.string("TRACING_EXPORTER", &cfg.TracingExporter)
+	e.float("TRACING_SAMPLE_RATIO", &cfg.TracingSampleRatio)
+
+	e.duration("REVIEW_BOMB_WINDOW", &cfg.ReviewBombWindow)
+	e.duration("REVIEW_BOMB_BASELINE", &cfg.ReviewBombBaseline)
+	e.int("REVIEW_BOMB_MIN_REVIEWS", &cfg.ReviewBombMinReviews)
+	e.float("REVIEW_BOMB_RATE_FACTOR", &cfg.ReviewBombRateFactor)
+	e.int("REVIEW_BOMB_LOW_RATING", &cfg.ReviewBombLowRating)
+	e.float("REVIEW_BOMB_LOW_SHARE", &cfg.ReviewBombLowShare)
+	e.bool("REVIEW_BOMB_EXCLUDE", &cfg.ReviewBombExclude)
+	e.duration("REVIEW_BOMB_SWEEP_INTERVAL", &cfg.ReviewBombSweepInterval)
+
+	e.int("MAX_PAGE_SIZE", &cfg.MaxPageSize)
+	e.int("MAX_REVIEW_TEXT_LENGTH", &cfg.MaxReviewTextLength)
+	e.bool("REVIEW_RELEASED_ONLY", &cfg.ReviewReleasedOnly)
+
+	e.duration("IDEMPOTENCY_TTL", &cfg.IdempotencyTTL)
+	e.duration("IDEMPOTENCY_LEASE", &cfg.IdempotencyLease)
+
+	e.int("FEED_HISTORY", &cfg.FeedHistory)
+	e.int("FEED_BUFFER", &cfg.FeedBuffer)
+	e.int("FEED_MAX_SUBSCRIBERS", &cfg.FeedMaxSubscribers)
+
+	e.rateLimits("RATE_LIMITS", &cfg.RateLimits)
+}
+
+// lookup treats an empty variable as unset, so a blank entry in an env file
+// cannot wipe out a value from the config file.
+func (e *envLoader) lookup(key string) (string, bool) {
+	value, ok := os.LookupEnv(key)
+	return value, ok && value != ""
+}
+
+func (e *envLoader) fail(key string, err error) {
+	e.errs = append(e.errs, fmt.Errorf("env %s: %w", key, err))
+}
+
+func (e *envLoader) string(key string, dst *string) {
+	if value, ok := e.lookup(key); ok {
+		*dst = value
+	}
+}
+
+// optionalString is string for settings where an empty value turns the
+// feature off: a variable that is set but empty still overrides dst.
+func (e *envLoader) optionalString(key string, dst *string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*dst = value
+	}
+}
+
+func (e *envLoader) int(key string, dst *int) {
+	if value, ok := e.lookup(key); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			e.fail(key, err)
+			return
+		}
+		*dst = n
+	}
+}
+
+func (e *envLoader) float(key string, dst *float64) {
+	if value, ok := e.lookup(key); ok {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			e.fail(key, err)
+			return
+		}
+		*dst = f
+	}
+}
+
+func (e *envLoader) bool(key string, dst *bool) {
+	if value, ok := e.lookup(key); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			e.fail(key, err)
+			return
+		}
+		*dst = b
+	}
+}
+
+// list reads a comma-separated value, ignoring empty items.
+func (e *envLoader) list(key string, dst *[]string) {
+	if value, ok := e.lookup(key); ok {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*dst = items
+	}
+}
+
+// tls reads PREFIX_ENABLED, PREFIX_CERT_FILE, PREFIX_KEY_FILE,
+// PREFIX_CA_FILE and PREFIX_SERVER_NAME.
+func (e *envLoader) tls(prefix string, dst *TLS) {
+	e.bool(prefix+"_ENABLED", &dst.Enabled)
+	e.string(prefix+"_CERT_FILE", &dst.CertFile)
+	e.string(prefix+"_KEY_FILE", &dst.KeyFile)
+	e.string(prefix+"_CA_FILE", &dst.CAFile)
+	e.string(prefix+"_SERVER_NAME", &dst.ServerName)
+}
+
+func (e *envLoader) duration(key string, dst *time.Duration) {
+	if value, ok := e.lookup(key); ok {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			e.fail(key, err)
+			return
+		}
+		*dst = d
+	}
+}
+
+// rateLimits parses "Method=rate:burst" pairs separated by commas, e.g.
+// "CreateReview=0.5:5,GetFeed=20:40".
+func (e *envLoader) rateLimits(key string, dst *map[string]RateLimit) {
+	value, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+
+	limits := make(map[string]RateLimit)
+	for _, entry := range strings.Split(value, ",") {
+		method, spec, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			e.fail(key, fmt.Errorf("%q is not Method=rate:burst", entry))
+			return
+		}
+
+		rateStr, burstStr, ok := strings.Cut(spec, ":")
+		if !ok {
+			e.fail(key, fmt.Errorf("%q is not Method=rate:burst", entry))
+			return
+		}
+
+		rate, err := strconv.ParseFloat(rateStr, 64)
+		if err != nil {
+			e.fail(key, err)
+			return
+		}
+
+		burst, err := strconv.Atoi(burstStr)
+		if err != nil {
+			e.fail(key, err)
+			return
+		}
+
+		limits[method] = RateLimit{Rate: rate, Burst: burst}
+	}
+
+	*dst = limits
 }
@@ -1,29 +1,42 @@
 package config
 
 import (
+	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func setEnv(t *testing.T, key, value string) {
-	if err := os.Setenv(key, value); err != nil {
-		t.Fatalf("error while setting env: %v", err)
-	}
+// setRequiredEnv provides every required setting so tests can focus on the
+// layer under test.
+func setRequiredEnv(t *testing.T) {
+	t.Setenv("DB_USER", "test_user")
+	t.Setenv("DB_NAME", "test_db")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "5432")
+	t.Setenv("GRPC_PORT", "50051")
+	t.Setenv("KAFKA_ADDR", "localhost:9092")
+	t.Setenv("GAME_SERVICE_ADDR", "games:50051")
+	t.Setenv("AUTH_SERVICE_ADDR", "auth:50051")
 }
 
-func TestLoad(t *testing.T) {
-	originalEnv := os.Getenv("DB_USER")
-	defer setEnv(t, "DB_USER", originalEnv)
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
 
-	setEnv(t, "DB_USER", "test_user")
-	setEnv(t, "DB_NAME", "test_db")
-	setEnv(t, "DB_HOST", "localhost")
-	setEnv(t, "DB_PORT", "5432")
-	setEnv(t, "ENV", "local")
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("ENV", "local")
 
-	cfg := Load()
+	cfg, err := Load(nil)
+	require.NoError(t, err)
 
 	assert.Equal(t, "test_user", cfg.DBUser)
 	assert.Equal(t, "test_db", cfg.DBName)
@@ -31,3 +44,258 @@ func TestLoad(t *testing.T) {
 	assert.Equal(t, "5432", cfg.DBPort)
 	assert.Equal(t, "local", cfg.Env)
 }
+
+func TestLoad_ReviewBombDefaults(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("REVIEW_BOMB_WINDOW", "30m")
+	t.Setenv("REVIEW_BOMB_EXCLUDE", "true")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, 30*time.Minute, cfg.ReviewBombWindow)
+	assert.Equal(t, 7*24*time.Hour, cfg.ReviewBombBaseline)
+	assert.Equal(t, 50, cfg.ReviewBombMinReviews)
+	assert.Equal(t, 0.7, cfg.ReviewBombLowShare)
+	assert.True(t, cfg.ReviewBombExclude)
+	assert.Equal(t, 5*time.Minute, cfg.ReviewBombSweepInterval)
+	assert.Equal(t, 20*time.Second, cfg.ShutdownTimeout)
+}
+
+func TestLoad_RateLimits(t *testing.T) {
+	setRequiredEnv(t)
+
+	t.Run("default", func(t *testing.T) {
+		cfg, err := Load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, RateLimit{Rate: 0.5, Burst: 5}, cfg.RateLimits["CreateReview"])
+	})
+
+	t.Run("parsed", func(t *testing.T) {
+		t.Setenv("RATE_LIMITS", "CreateReview=2:10, GetFeed=20:40")
+		cfg, err := Load(nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]RateLimit{
+			"CreateReview": {Rate: 2, Burst: 10},
+			"GetFeed":      {Rate: 20, Burst: 40},
+		}, cfg.RateLimits)
+	})
+
+	t.Run("malformed is reported", func(t *testing.T) {
+		t.Setenv("RATE_LIMITS", "CreateReview=fast")
+		_, err := Load(nil)
+		assert.ErrorContains(t, err, "RATE_LIMITS")
+	})
+}
+
+func TestLoad_Layers(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("DB_HOST", "")
+
+	path := writeFile(t, "config.yaml", `
+db_host: file-host
+grpc_port: "6000"
+shutdown_timeout: 45s
+rate_limits:
+  GetFeed:
+    rate: 20
+    burst: 40
+`)
+	t.Setenv("GRPC_PORT", "7000")
+	t.Setenv("SHUTDOWN_DELAY", "2s")
+
+	cfg, err := Load([]string{"--config", path, "--grpc-port", "8000", "--shutdown-timeout", "1m"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "file-host", cfg.DBHost, "file value without env override")
+	assert.Equal(t, "8000", cfg.GRPCPort, "flag wins over env and file")
+	assert.Equal(t, time.Minute, cfg.ShutdownTimeout)
+	assert.Equal(t, 2*time.Second, cfg.ShutdownDelay)
+	assert.Equal(t, RateLimit{Rate: 20, Burst: 40}, cfg.RateLimits["GetFeed"])
+}
+
+func TestLoad_UnknownFileField(t *testing.T) {
+	setRequiredEnv(t)
+	path := writeFile(t, "config.yaml", "grpc_prot: 6000\n")
+
+	_, err := Load([]string{"--config", path})
+	assert.ErrorContains(t, err, "grpc_prot")
+}
+
+func TestLoad_HTTPPort(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Empty(t, cfg.HTTPPort, "gateway is off by default")
+
+	path := writeFile(t, "config.yaml", "http_port: \"8080\"\n")
+	cfg, err = Load([]string{"--config", path})
+	require.NoError(t, err)
+	assert.Equal(t, "8080", cfg.HTTPPort)
+
+	t.Setenv("HTTP_PORT", "")
+	cfg, err = Load([]string{"--config", path})
+	require.NoError(t, err)
+	assert.Empty(t, cfg.HTTPPort, "empty env disables the gateway")
+}
+
+func TestLoad_PasswordFile(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("DB_PASSWORD", "from-env")
+	t.Setenv("DB_PASSWORD_FILE", writeFile(t, "password", "s3cret\n"))
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.DBPassword)
+
+	t.Setenv("DB_PASSWORD_FILE", filepath.Join(t.TempDir(), "missing"))
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "db_password_file")
+}
+
+func TestLoadDatabase(t *testing.T) {
+	t.Setenv("DB_USER", "test_user")
+	t.Setenv("DB_NAME", "test_db")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "5432")
+
+	cfg, err := LoadDatabase(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "localhost", cfg.DBHost)
+
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "kafka_addr is required")
+
+	t.Setenv("DB_PORT", "")
+	_, err = LoadDatabase(nil)
+	assert.ErrorContains(t, err, "db_port is required")
+}
+
+func TestLoad_UnexpectedArgument(t *testing.T) {
+	setRequiredEnv(t)
+
+	_, err := Load([]string{"--env", "local", "extra"})
+	assert.ErrorContains(t, err, `unexpected argument "extra"`)
+}
+
+func TestLoad_TLS(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("SERVER_TLS_ENABLED", "true")
+	t.Setenv("SERVER_TLS_CERT_FILE", "/tls/tls.crt")
+	t.Setenv("SERVER_TLS_KEY_FILE", "/tls/tls.key")
+	t.Setenv("SERVER_TLS_CA_FILE", "/tls/ca.crt")
+	t.Setenv("GAME_SERVICE_TLS_ENABLED", "true")
+	t.Setenv("GAME_SERVICE_TLS_SERVER_NAME", "games.internal")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, TLS{Enabled: true, CertFile: "/tls/tls.crt", KeyFile: "/tls/tls.key", CAFile: "/tls/ca.crt"}, cfg.ServerTLS)
+	assert.Equal(t, TLS{Enabled: true, ServerName: "games.internal"}, cfg.GameServiceTLS)
+	assert.False(t, cfg.AuthServiceTLS.Enabled)
+
+	t.Setenv("SERVER_TLS_KEY_FILE", "")
+	t.Setenv("AUTH_SERVICE_TLS_CERT_FILE", "/tls/client.crt")
+
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "server_tls needs cert_file and key_file when enabled")
+	assert.ErrorContains(t, err, "auth_service_tls.cert_file and auth_service_tls.key_file must be set together")
+}
+
+func TestLoad_Auth(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "gateway", cfg.AuthMode)
+
+	t.Setenv("AUTH_MODE", "jwt")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "auth_mode jwt needs")
+
+	t.Setenv("AUTH_HMAC_SECRET_FILE", writeFile(t, "secret", "s3cret\n"))
+	t.Setenv("AUTH_PUBLIC_KEY_FILES", "/keys/a.pem, /keys/b.pem,")
+
+	cfg, err = Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", cfg.AuthHMACSecret)
+	assert.Equal(t, []string{"/keys/a.pem", "/keys/b.pem"}, cfg.AuthPublicKeyFiles)
+
+	var out bytes.Buffer
+	require.NoError(t, cfg.Print(&out))
+	assert.NotContains(t, out.String(), "s3cret")
+
+	t.Setenv("AUTH_MODE", "oauth")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "auth_mode must be one of gateway, jwt")
+}
+
+func TestValidate_ReportsAllProblems(t *testing.T) {
+	cfg := Default()
+	cfg.DBPort = "not-a-port"
+	cfg.HTTPPort = "http"
+	cfg.TracingExporter = "jaeger"
+	cfg.HealthCheckTimeout = 0
+	cfg.ShutdownDelay = -time.Second
+
+	err := cfg.Validate()
+	require.Error(t, err)
+
+	for _, want := range []string{
+		"db_host is required",
+		"grpc_port is required",
+		"kafka_addr is required",
+		"db_port must be a port number",
+		"http_port must be a port number",
+		"tracing_exporter must be one of",
+		"health_check_timeout must be positive",
+		"shutdown_delay must not be negative",
+	} {
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestConfig_Print(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("DB_PASSWORD", "hunter2")
+
+	cfg, err := Load([]string{"--print-config"})
+	require.NoError(t, err)
+	assert.True(t, cfg.PrintConfig)
+
+	var buf bytes.Buffer
+	require.NoError(t, cfg.Print(&buf))
+
+	assert.NotContains(t, buf.String(), "hunter2")
+	assert.Contains(t, buf.String(), "db_password: '[REDACTED]'")
+	assert.Contains(t, buf.String(), "shutdown_timeout: 20s")
+	assert.Equal(t, "hunter2", cfg.DBPassword, "printing must not modify the config")
+}
+
+func TestLoad_Idempotency(t *testing.T) {
+	setRequiredEnv(t)
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 24*time.Hour, cfg.IdempotencyTTL)
+	assert.Equal(t, time.Minute, cfg.IdempotencyLease)
+
+	t.Setenv("IDEMPOTENCY_TTL", "30s")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "idempotency_lease must not be longer than idempotency_ttl")
+}
+
+func TestLoad_Feed(t *testing.T) {
+	setRequiredEnv(t)
+	t.Setenv("FEED_BUFFER", "16")
+
+	cfg, err := Load(nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1000, cfg.FeedHistory)
+	assert.Equal(t, 16, cfg.FeedBuffer)
+
+	t.Setenv("FEED_MAX_SUBSCRIBERS", "0")
+	_, err = Load(nil)
+	assert.ErrorContains(t, err, "feed_max_subscribers must be at least 1")
+}
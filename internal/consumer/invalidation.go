@@ -0,0 +1,192 @@
+// Package consumer reads events published by other services.
+package consumer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/kafka-go"
+)
+
+type KafkaReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	Close() error
+}
+
+// Invalidator drops a cached entity.
+type Invalidator interface {
+	Invalidate(id string)
+}
+
+// InvalidationConsumer evicts cache entries for entities changed in another
+// service. The entity ID is the message key or, for keyless messages, the
+// idField of the JSON value.
+type InvalidationConsumer struct {
+	topic   string
+	idField string
+	target  Invalidator
+	retry   time.Duration
+
+	// partitions lists the partitions of topic and newReader opens one.
+	partitions func(ctx context.Context) ([]int, error)
+	newReader  func(partition int) KafkaReader
+
+	mu      sync.Mutex
+	readers []KafkaReader
+	closed  bool
+}
+
+// NewInvalidationConsumer reads every partition of topic without a consumer
+// group, starting at the newest offset: each replica keeps its own cache, so
+// it needs every event, and older ones are covered by the cache TTL. Nothing
+// is committed, so no group is left behind on the broker by replicas that
+// are gone. Partitions added while running are picked up on restart.
+func NewInvalidationConsumer(broker, topic, idField string, target Invalidator) *InvalidationConsumer {
+	return &InvalidationConsumer{
+		topic:   topic,
+		idField: idField,
+		target:  target,
+		retry:   time.Second,
+		partitions: func(ctx context.Context) ([]int, error) {
+			return readPartitions(ctx, broker, topic)
+		},
+		newReader: func(partition int) KafkaReader {
+			return kafka.NewReader(kafka.ReaderConfig{
+				Brokers:     []string{broker},
+				Topic:       topic,
+				Partition:   partition,
+				StartOffset: kafka.LastOffset,
+			})
+		},
+	}
+}
+
+func readPartitions(ctx context.Context, broker, topic string) ([]int, error) {
+	conn, err := kafka.DialContext(ctx, "tcp", broker)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			log.Debug().Err(err).Str("broker", broker).Msg("cache invalidation: failed to close connection")
+		}
+	}()
+
+	partitions, err := conn.ReadPartitions(topic)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(partitions))
+	for _, p := range partitions {
+		ids = append(ids, p.ID)
+	}
+
+	return ids, nil
+}
+
+// Run consumes until ctx is cancelled or the consumer is closed.
+func (c *InvalidationConsumer) Run(ctx context.Context) {
+	var partitions []int
+	for {
+		var err error
+		if partitions, err = c.partitions(ctx); err == nil {
+			break
+		}
+
+		log.Warn().Err(err).Str("topic", c.topic).Msg("cache invalidation: failed to list partitions")
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(c.retry):
+		}
+	}
+
+	var wg sync.WaitGroup
+	for _, partition := range partitions {
+		reader, ok := c.open(partition)
+		if !ok {
+			break
+		}
+		wg.Go(func() { c.consume(ctx, reader) })
+	}
+	wg.Wait()
+}
+
+// open returns a reader for partition, or false once the consumer is closed.
+func (c *InvalidationConsumer) open(partition int) (KafkaReader, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, false
+	}
+
+	reader := c.newReader(partition)
+	c.readers = append(c.readers, reader)
+
+	return reader, true
+}
+
+func (c *InvalidationConsumer) consume(ctx context.Context, reader KafkaReader) {
+	for {
+		msg, err := reader.ReadMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil || errors.Is(err, io.EOF) {
+				return
+			}
+
+			log.Warn().Err(err).Str("topic", c.topic).Msg("cache invalidation: failed to read message")
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(c.retry):
+			}
+			continue
+		}
+
+		id := c.entityID(msg)
+		if id == "" {
+			log.Debug().Str("topic", c.topic).Int("partition", msg.Partition).Int64("offset", msg.Offset).Msg("cache invalidation: message without entity id")
+			continue
+		}
+
+		c.target.Invalidate(id)
+	}
+}
+
+func (c *InvalidationConsumer) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+
+	var errs []error
+	for _, reader := range c.readers {
+		errs = append(errs, reader.Close())
+	}
+	c.readers = nil
+
+	return errors.Join(errs...)
+}
+
+func (c *InvalidationConsumer) entityID(msg kafka.Message) string {
+	if len(msg.Key) > 0 {
+		return string(msg.Key)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(msg.Value, &fields); err != nil {
+		return ""
+	}
+
+	id, _ := fields[c.idField].(string)
+	return id
+}
@@ -0,0 +1,111 @@
+package consumer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeReader replays messages and errors, then reports the reader closed.
+type fakeReader struct {
+	results []any
+}
+
+func (f *fakeReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if len(f.results) == 0 {
+		return kafka.Message{}, io.EOF
+	}
+
+	next := f.results[0]
+	f.results = f.results[1:]
+
+	if err, ok := next.(error); ok {
+		return kafka.Message{}, err
+	}
+	return next.(kafka.Message), nil
+}
+
+func (f *fakeReader) Close() error { return nil }
+
+// recorder collects invalidated IDs; partitions are consumed concurrently.
+type recorder struct {
+	mu  sync.Mutex
+	ids []string
+}
+
+func (r *recorder) Invalidate(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ids = append(r.ids, id)
+}
+
+func newTestConsumer(target Invalidator, partitions func(ctx context.Context) ([]int, error), readers map[int]*fakeReader) *InvalidationConsumer {
+	return &InvalidationConsumer{
+		topic:      "game_events",
+		idField:    "game_id",
+		target:     target,
+		retry:      time.Millisecond,
+		partitions: partitions,
+		newReader:  func(partition int) KafkaReader { return readers[partition] },
+	}
+}
+
+func TestInvalidationConsumer_Run(t *testing.T) {
+	t.Run("reads every partition", func(t *testing.T) {
+		var invalidated recorder
+		c := newTestConsumer(&invalidated, func(context.Context) ([]int, error) { return []int{0, 1}, nil }, map[int]*fakeReader{
+			0: {results: []any{
+				kafka.Message{Key: []byte("g1")},
+				errors.New("broker unavailable"),
+				kafka.Message{Value: []byte(`{"game_id":"g2","event":"updated"}`)},
+			}},
+			1: {results: []any{
+				kafka.Message{Value: []byte(`not json`)},
+				kafka.Message{Value: []byte(`{"other":"field"}`)},
+				kafka.Message{Key: []byte("g3")},
+			}},
+		})
+
+		c.Run(context.Background())
+
+		assert.ElementsMatch(t, []string{"g1", "g2", "g3"}, invalidated.ids)
+		assert.NoError(t, c.Close())
+	})
+
+	t.Run("partition lookup is retried", func(t *testing.T) {
+		var invalidated recorder
+		attempts := 0
+		c := newTestConsumer(&invalidated, func(context.Context) ([]int, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, errors.New("broker unavailable")
+			}
+			return []int{0}, nil
+		}, map[int]*fakeReader{
+			0: {results: []any{kafka.Message{Key: []byte("g1")}}},
+		})
+
+		c.Run(context.Background())
+
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, []string{"g1"}, invalidated.ids)
+	})
+
+	t.Run("closed consumer opens no readers", func(t *testing.T) {
+		var invalidated recorder
+		c := newTestConsumer(&invalidated, func(context.Context) ([]int, error) { return []int{0}, nil }, nil)
+		c.newReader = func(int) KafkaReader {
+			t.Fatal("reader opened after Close")
+			return nil
+		}
+
+		assert.NoError(t, c.Close())
+		c.Run(context.Background())
+	})
+}
@@ -6,11 +6,8 @@ import (
 	"social-service/internal/config"
 
 	_ "github.com/lib/pq"
-	"github.com/pressly/goose/v3"
 )
 
-var DB *sql.DB
-
 func Init(cfg *config.Config) (*sql.DB, error) {
 
 	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", cfg.DBHost, cfg.DBPort, cfg.DBUser, cfg.DBPassword, cfg.DBName)
@@ -24,20 +21,5 @@ func Init(cfg *config.Config) (*sql.DB, error) {
 		return nil, err
 	}
 
-	DB = db
-
-	return DB, nil
-}
-
-func Migrate(db *sql.DB) error {
-
-	if err := goose.SetDialect("postgres"); err != nil {
-		return err
-	}
-
-	if err := goose.Up(db, "migrations"); err != nil {
-		return err
-	}
-
-	return nil
+	return db, nil
 }
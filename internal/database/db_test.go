@@ -1,8 +1,14 @@
 package database
 
 import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
 	"social-service/internal/config"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
@@ -26,9 +32,56 @@ func TestMigrate_Error(t *testing.T) {
 		_ = db.Close()
 	}()
 
-	err = Migrate(db)
-	assert.Error(t, err)
-	assert.Contains(t, err.Error(), "migrations directory does not exist")
+	mock.ExpectQuery("goose_db_version").WillReturnError(errors.New("connection refused"))
+
+	err = Migrate(context.Background(), db)
+	assert.ErrorContains(t, err, "connection refused")
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRunMigration_UnknownCommand(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() {
+		mock.ExpectClose()
+		_ = db.Close()
+	}()
+
+	err = RunMigration(context.Background(), db, "sideways", io.Discard)
+	assert.ErrorContains(t, err, `unknown migrate command "sideways"`)
 
 	assert.NoError(t, mock.ExpectationsWereMet())
 }
+
+func TestEmbeddedMigrations(t *testing.T) {
+	db, _, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	migrator, err := newMigrator(db)
+	assert.NoError(t, err)
+
+	sources := migrator.ListSources()
+	assert.NotEmpty(t, sources)
+	assert.Equal(t, int64(202512111501), sources[0].Version)
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 10, 19, 10, 40, 0, 0, time.UTC)
+
+	path, err := CreateMigration(dir, "review_versions", now)
+	assert.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "202610191040_review_versions.sql"), path)
+
+	content, err := os.ReadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "-- +goose Up\n\n-- +goose Down\n", string(content))
+
+	_, err = CreateMigration(dir, "other", now)
+	assert.ErrorContains(t, err, "already used by 202610191040_review_versions.sql")
+
+	_, err = CreateMigration(dir, "Bad Name", now.Add(time.Minute))
+	assert.ErrorContains(t, err, "lower snake case")
+}
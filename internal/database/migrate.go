@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"social-service/migrations"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+	"github.com/rs/zerolog/log"
+)
+
+// Commands accepted by RunMigration.
+const (
+	MigrateUp     = "up"
+	MigrateDown   = "down"
+	MigrateStatus = "status"
+	MigrateRedo   = "redo"
+)
+
+// migrationVersion matches the prefix of the existing migration files.
+const migrationVersion = "200601021504"
+
+var migrationName = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// newMigrator builds a goose provider over the embedded migrations. A
+// Postgres advisory lock is held while migrating, so replicas started with
+// migrate_on_start and a separate migrate job never apply the same version
+// twice.
+func newMigrator(db *sql.DB) (*goose.Provider, error) {
+	locker, err := lock.NewPostgresSessionLocker()
+	if err != nil {
+		return nil, err
+	}
+
+	return goose.NewProvider(goose.DialectPostgres, db, migrations.FS, goose.WithSessionLocker(locker))
+}
+
+// Migrate applies every pending migration.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	results, err := migrator.Up(ctx)
+	for _, result := range results {
+		log.Info().Str("migration", result.Source.Path).Dur("duration", result.Duration).Msg("migration applied")
+	}
+
+	return err
+}
+
+// RunMigration executes one of the migrate subcommands and reports what it
+// did to out.
+func RunMigration(ctx context.Context, db *sql.DB, command string, out io.Writer) error {
+	switch command {
+	case MigrateUp, MigrateDown, MigrateStatus, MigrateRedo:
+	default:
+		return fmt.Errorf("unknown migrate command %q, want up, down, status or redo", command)
+	}
+
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case MigrateUp:
+		results, err := migrator.Up(ctx)
+		printResults(out, results...)
+		if err == nil && len(results) == 0 {
+			_, _ = fmt.Fprintln(out, "no pending migrations")
+		}
+		return err
+
+	case MigrateDown:
+		result, err := migrator.Down(ctx)
+		printResults(out, result)
+		return err
+
+	case MigrateRedo:
+		result, err := migrator.Down(ctx)
+		printResults(out, result)
+		if err != nil {
+			return err
+		}
+
+		result, err = migrator.UpByOne(ctx)
+		printResults(out, result)
+		return err
+
+	default:
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "STATE\tAPPLIED AT\tMIGRATION")
+		for _, status := range statuses {
+			appliedAt := "-"
+			if !status.AppliedAt.IsZero() {
+				appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", status.State, appliedAt, status.Source.Path)
+		}
+		return tw.Flush()
+	}
+}
+
+func printResults(out io.Writer, results ...*goose.MigrationResult) {
+	for _, result := range results {
+		if result != nil && result.Error == nil {
+			_, _ = fmt.Fprintf(out, "%s %s (%s)\n", result.Direction, result.Source.Path, result.Duration.Round(time.Millisecond))
+		}
+	}
+}
+
+// CreateMigration writes an empty goose SQL migration named after now into
+// dir and returns its path. Names are lower snake case, like the existing
+// files.
+func CreateMigration(dir, name string, now time.Time) (string, error) {
+	if !migrationName.MatchString(name) {
+		return "", fmt.Errorf("migration name %q must be lower snake case", name)
+	}
+
+	version := now.UTC().Format(migrationVersion)
+
+	existing, err := filepath.Glob(filepath.Join(dir, version+"_*.sql"))
+	if err != nil {
+		return "", err
+	}
+	if len(existing) > 0 {
+		return "", fmt.Errorf("migration version %s is already used by %s", version, filepath.Base(existing[0]))
+	}
+
+	path := filepath.Join(dir, version+"_"+name+".sql")
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	_, err = io.WriteString(file, "-- +goose Up\n\n-- +goose Down\n")
+
+	return path, errors.Join(err, file.Close())
+}
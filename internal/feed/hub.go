@@ -0,0 +1,235 @@
+// Package feed broadcasts newly created reviews to live subscribers.
+package feed
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"social-service/internal/metrics"
+	"social-service/internal/model"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCursorExpired means the events after a cursor are no longer
+	// retained, or the cursor was issued by another hub.
+	ErrCursorExpired = errors.New("cursor can no longer be resumed from")
+	ErrInvalidCursor = errors.New("malformed cursor")
+	// ErrSlowSubscriber ends a subscription whose buffer filled up.
+	ErrSlowSubscriber     = errors.New("subscriber fell behind")
+	ErrTooManySubscribers = errors.New("too many live feed subscribers")
+)
+
+// Event is a review as broadcast, numbered in publish order.
+type Event struct {
+	Seq    uint64
+	Review *model.Review
+}
+
+// Filter selects the reviews a subscriber receives. An empty set matches
+// everything.
+type Filter struct {
+	GameIDs map[uuid.UUID]struct{}
+	UserIDs map[uuid.UUID]struct{}
+}
+
+func (f Filter) Match(review *model.Review) bool {
+	if len(f.GameIDs) > 0 {
+		if _, ok := f.GameIDs[review.GameID]; !ok {
+			return false
+		}
+	}
+
+	if len(f.UserIDs) > 0 {
+		if _, ok := f.UserIDs[review.UserID]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+type Options struct {
+	// History is how many recent events are kept for resuming.
+	History int
+	// Buffer is how many events may queue for a subscriber before it is
+	// disconnected as too slow.
+	Buffer int
+	// MaxSubscribers caps concurrent subscriptions.
+	MaxSubscribers int
+}
+
+// Hub fans reviews out to subscribers without ever blocking the publisher: a
+// subscriber that cannot keep up is dropped and resumes from its cursor,
+// replaying from the retained history. Cursors are only valid against the
+// hub that issued them, since history is kept in memory.
+type Hub struct {
+	opts  Options
+	epoch string
+
+	mu      sync.Mutex
+	seq     uint64
+	history []Event
+	subs    map[*Subscription]struct{}
+}
+
+func NewHub(opts Options) *Hub {
+	return &Hub{
+		opts:  opts,
+		epoch: strconv.FormatUint(rand.Uint64(), 36),
+		subs:  make(map[*Subscription]struct{}),
+	}
+}
+
+// Publish broadcasts review to the matching subscribers.
+func (h *Hub) Publish(review *model.Review) {
+	copied := *review
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.seq++
+	event := Event{Seq: h.seq, Review: &copied}
+
+	h.history = append(h.history, event)
+	if len(h.history) > h.opts.History {
+		h.history = h.history[len(h.history)-h.opts.History:]
+	}
+
+	for sub := range h.subs {
+		if !sub.filter.Match(event.Review) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+			h.remove(sub, ErrSlowSubscriber)
+			metrics.ObserveFeedDisconnect()
+		}
+	}
+}
+
+// Subscribe starts a subscription. With a cursor, the retained events after
+// it are delivered first, so nothing is missed or repeated across a
+// reconnect.
+func (h *Hub) Subscribe(filter Filter, cursor string) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.subs) >= h.opts.MaxSubscribers {
+		return nil, ErrTooManySubscribers
+	}
+
+	var replay []Event
+	if cursor != "" {
+		after, err := h.parseCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if after < h.seq {
+			if len(h.history) == 0 || after+1 < h.history[0].Seq {
+				return nil, ErrCursorExpired
+			}
+
+			for _, event := range h.history[after+1-h.history[0].Seq:] {
+				if filter.Match(event.Review) {
+					replay = append(replay, event)
+				}
+			}
+		}
+	}
+
+	sub := &Subscription{
+		hub:    h,
+		filter: filter,
+		events: make(chan Event, h.opts.Buffer+len(replay)),
+		done:   make(chan struct{}),
+	}
+	for _, event := range replay {
+		sub.events <- event
+	}
+
+	h.subs[sub] = struct{}{}
+	metrics.SetFeedSubscribers(len(h.subs))
+
+	return sub, nil
+}
+
+// Cursor identifies event for resuming after it.
+func (h *Hub) Cursor(event Event) string {
+	return h.epoch + "-" + strconv.FormatUint(event.Seq, 10)
+}
+
+func (h *Hub) parseCursor(cursor string) (uint64, error) {
+	epoch, raw, ok := strings.Cut(cursor, "-")
+	if !ok {
+		return 0, ErrInvalidCursor
+	}
+
+	seq, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+
+	if epoch != h.epoch {
+		return 0, ErrCursorExpired
+	}
+
+	if seq > h.seq {
+		return 0, fmt.Errorf("%w: it is ahead of the feed", ErrInvalidCursor)
+	}
+
+	return seq, nil
+}
+
+// remove ends sub with err; the caller holds the lock.
+func (h *Hub) remove(sub *Subscription, err error) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+
+	delete(h.subs, sub)
+	sub.err = err
+	close(sub.done)
+	metrics.SetFeedSubscribers(len(h.subs))
+}
+
+// Subscription receives the events of one subscriber.
+type Subscription struct {
+	hub    *Hub
+	filter Filter
+	events chan Event
+	done   chan struct{}
+	err    error
+}
+
+// Events delivers matching events in publish order.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Done is closed when the hub ends the subscription; Err then says why.
+func (s *Subscription) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Subscription) Err() error {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	return s.err
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+
+	s.hub.remove(s, nil)
+}
@@ -0,0 +1,177 @@
+package feed
+
+import (
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestHub() *Hub {
+	return NewHub(Options{History: 3, Buffer: 2, MaxSubscribers: 2})
+}
+
+func newReview(gameID, userID uuid.UUID) *model.Review {
+	return &model.Review{Id: uuid.New(), GameID: gameID, UserID: userID, Rating: 50, CreatedAt: time.Now()}
+}
+
+// receive takes the events already queued for sub.
+func receive(sub *Subscription) []Event {
+	var events []Event
+	for {
+		select {
+		case event := <-sub.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func reviewIDs(events []Event) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.Review.Id)
+	}
+	return ids
+}
+
+func TestFilter_Match(t *testing.T) {
+	game, user := uuid.New(), uuid.New()
+	review := newReview(game, user)
+
+	assert.True(t, Filter{}.Match(review))
+	assert.True(t, Filter{GameIDs: map[uuid.UUID]struct{}{game: {}}}.Match(review))
+	assert.True(t, Filter{GameIDs: map[uuid.UUID]struct{}{game: {}}, UserIDs: map[uuid.UUID]struct{}{user: {}}}.Match(review))
+	assert.False(t, Filter{UserIDs: map[uuid.UUID]struct{}{uuid.New(): {}}}.Match(review))
+	assert.False(t, Filter{GameIDs: map[uuid.UUID]struct{}{game: {}}, UserIDs: map[uuid.UUID]struct{}{uuid.New(): {}}}.Match(review))
+}
+
+func TestHub_Publish(t *testing.T) {
+	hub := newTestHub()
+	game := uuid.New()
+
+	all, err := hub.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+	defer all.Close()
+
+	byGame, err := hub.Subscribe(Filter{GameIDs: map[uuid.UUID]struct{}{game: {}}}, "")
+	require.NoError(t, err)
+	defer byGame.Close()
+
+	first, second := newReview(game, uuid.New()), newReview(uuid.New(), uuid.New())
+	hub.Publish(first)
+	hub.Publish(second)
+
+	assert.Equal(t, []uuid.UUID{first.Id, second.Id}, reviewIDs(receive(all)))
+	assert.Equal(t, []uuid.UUID{first.Id}, reviewIDs(receive(byGame)))
+}
+
+func TestHub_Resume(t *testing.T) {
+	hub := newTestHub()
+
+	sub, err := hub.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+
+	reviews := []*model.Review{newReview(uuid.New(), uuid.New()), newReview(uuid.New(), uuid.New())}
+	for _, review := range reviews {
+		hub.Publish(review)
+	}
+
+	events := receive(sub)
+	require.Len(t, events, 2)
+	sub.Close()
+
+	missed := newReview(uuid.New(), uuid.New())
+	hub.Publish(missed)
+
+	t.Run("replays after the cursor", func(t *testing.T) {
+		resumed, err := hub.Subscribe(Filter{}, hub.Cursor(events[0]))
+		require.NoError(t, err)
+		defer resumed.Close()
+
+		assert.Equal(t, []uuid.UUID{reviews[1].Id, missed.Id}, reviewIDs(receive(resumed)))
+	})
+
+	t.Run("latest cursor replays nothing", func(t *testing.T) {
+		resumed, err := hub.Subscribe(Filter{}, hub.Cursor(Event{Seq: 3}))
+		require.NoError(t, err)
+		defer resumed.Close()
+
+		assert.Empty(t, receive(resumed))
+	})
+
+	t.Run("history exhausted", func(t *testing.T) {
+		hub.Publish(newReview(uuid.New(), uuid.New()))
+		hub.Publish(newReview(uuid.New(), uuid.New()))
+
+		_, err := hub.Subscribe(Filter{}, hub.Cursor(events[0]))
+		assert.ErrorIs(t, err, ErrCursorExpired)
+	})
+
+	t.Run("cursor from another hub", func(t *testing.T) {
+		_, err := newTestHub().Subscribe(Filter{}, hub.Cursor(events[1]))
+		assert.ErrorIs(t, err, ErrCursorExpired)
+	})
+
+	t.Run("malformed cursor", func(t *testing.T) {
+		_, err := hub.Subscribe(Filter{}, "nonsense")
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+
+	t.Run("cursor ahead of the feed", func(t *testing.T) {
+		_, err := hub.Subscribe(Filter{}, hub.Cursor(Event{Seq: 100}))
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+}
+
+func TestHub_SlowSubscriber(t *testing.T) {
+	hub := newTestHub()
+
+	slow, err := hub.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+
+	for range 3 {
+		hub.Publish(newReview(uuid.New(), uuid.New()))
+	}
+
+	select {
+	case <-slow.Done():
+	default:
+		t.Fatal("slow subscriber was not dropped")
+	}
+	assert.ErrorIs(t, slow.Err(), ErrSlowSubscriber)
+
+	// What it did receive leads back to what it missed.
+	events := receive(slow)
+	require.Len(t, events, 2)
+
+	resumed, err := hub.Subscribe(Filter{}, hub.Cursor(events[1]))
+	require.NoError(t, err)
+	defer resumed.Close()
+	assert.Len(t, receive(resumed), 1)
+}
+
+func TestHub_MaxSubscribers(t *testing.T) {
+	hub := newTestHub()
+
+	first, err := hub.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+	second, err := hub.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+	defer second.Close()
+
+	_, err = hub.Subscribe(Filter{}, "")
+	assert.ErrorIs(t, err, ErrTooManySubscribers)
+
+	first.Close()
+	first.Close()
+
+	third, err := hub.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+	third.Close()
+	assert.NoError(t, third.Err())
+}
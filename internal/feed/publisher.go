@@ -0,0 +1,44 @@
+package feed
+
+import (
+	"context"
+	"social-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// BanLookup reports a user's shadow ban, nil when there is none.
+type BanLookup interface {
+	GetShadowBan(ctx context.Context, userID uuid.UUID) (*model.ShadowBan, error)
+}
+
+// Publisher feeds the hub the reviews everyone may see. As in GetFeed,
+// reviews by shadow-banned users are left out.
+type Publisher struct {
+	hub  *Hub
+	bans BanLookup
+}
+
+func NewPublisher(hub *Hub, bans BanLookup) *Publisher {
+	return &Publisher{
+		hub:  hub,
+		bans: bans,
+	}
+}
+
+func (p *Publisher) Publish(ctx context.Context, review *model.Review) {
+	ban, err := p.bans.GetShadowBan(ctx, review.UserID)
+	if err != nil {
+		// Broadcasting a banned user's review cannot be taken back, so an
+		// unknown ban status keeps the review off the live feed.
+		log.Ctx(ctx).Warn().Err(err).Str("review_id", review.Id.String()).Msg("feed: shadow ban check failed, review not broadcast")
+		return
+	}
+
+	if ban != nil {
+		return
+	}
+
+	p.hub.Publish(review)
+}
@@ -0,0 +1,49 @@
+package feed
+
+import (
+	"context"
+	"errors"
+	"social-service/internal/model"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBans struct {
+	banned map[uuid.UUID]bool
+	err    error
+}
+
+func (f fakeBans) GetShadowBan(ctx context.Context, userID uuid.UUID) (*model.ShadowBan, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.banned[userID] {
+		return &model.ShadowBan{UserID: userID}, nil
+	}
+	return nil, nil
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	banned := uuid.New()
+	hub := newTestHub()
+
+	sub, err := hub.Subscribe(Filter{}, "")
+	require.NoError(t, err)
+	defer sub.Close()
+
+	publisher := NewPublisher(hub, fakeBans{banned: map[uuid.UUID]bool{banned: true}})
+	visible := newReview(uuid.New(), uuid.New())
+
+	publisher.Publish(context.Background(), newReview(uuid.New(), banned))
+	publisher.Publish(context.Background(), visible)
+
+	assert.Equal(t, []uuid.UUID{visible.Id}, reviewIDs(receive(sub)))
+
+	t.Run("ban lookup fails", func(t *testing.T) {
+		NewPublisher(hub, fakeBans{err: errors.New("db down")}).Publish(context.Background(), newReview(uuid.New(), uuid.New()))
+		assert.Empty(t, receive(sub))
+	})
+}
@@ -0,0 +1,73 @@
+package gateway
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/viktoralyoshin/utils/pkg/errs"
+	"google.golang.org/genproto/googleapis/rpc/code"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// errorBody is the JSON shape of every failed response, e.g.
+//
+//	{"error": {"code": 404, "status": "NOT_FOUND", "message": "game not found"}}
+//
+// Details carry the google.rpc error details of the gRPC status, such as the
+// field violations of a BadRequest.
+type errorBody struct {
+	Error errorStatus `json:"error"`
+}
+
+type errorStatus struct {
+	Code    int               `json:"code"`
+	Status  string            `json:"status"`
+	Message string            `json:"message"`
+	Details []json.RawMessage `json:"details,omitempty"`
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	st := status.Convert(err)
+	httpStatus, message := httpStatus(st)
+
+	writeStatus(w, httpStatus, message, st)
+}
+
+// writeStatus writes st with an explicit HTTP status, for the few responses
+// no gRPC code maps to.
+func writeStatus(w http.ResponseWriter, httpStatus int, message string, st *status.Status) {
+	body := errorBody{Error: errorStatus{
+		Code:    httpStatus,
+		Status:  code.Code(st.Code()).String(),
+		Message: message,
+	}}
+
+	// Internal errors keep their details to the logs.
+	if httpStatus != http.StatusInternalServerError {
+		for _, detail := range st.Proto().GetDetails() {
+			if raw, err := protojson.Marshal(detail); err == nil {
+				body.Error.Details = append(body.Error.Details, raw)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// httpStatus extends errs.HTTPStatus with the codes it reports as internal
+// errors although they are the caller's to fix.
+func httpStatus(st *status.Status) (int, string) {
+	switch st.Code() {
+	case codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest, st.Message()
+	case codes.Canceled:
+		// Non-standard, as used by nginx: the client closed the request.
+		return 499, st.Message()
+	}
+
+	return errs.HTTPStatus(st.Err())
+}
@@ -0,0 +1,253 @@
+// Package gateway serves SocialService over HTTP/JSON for clients that cannot
+// speak gRPC.
+package gateway
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"social-service/internal/handlers"
+	"social-service/internal/idempotency"
+	"social-service/internal/middleware"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+//go:embed openapi.json
+var openAPI []byte
+
+// maxBodySize bounds request bodies; a review is a few kilobytes at most.
+const maxBodySize = 1 << 20
+
+// forwardedHeaders are the HTTP headers passed to the handlers as gRPC
+// metadata. Everything else stays at the HTTP layer.
+var forwardedHeaders = []string{
+	"authorization",
+	middleware.UserIDHeader,
+	middleware.UserRoleHeader,
+	middleware.RequestIDHeader,
+	idempotency.KeyHeader,
+	handlers.ReadMaskHeader,
+}
+
+var marshaler = protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true}
+
+// Gateway translates HTTP requests into SocialService calls. Every call runs
+// through the given interceptor, normally the server's own chain, so
+// authentication, rate limits, validation and idempotency behave exactly as
+// they do over gRPC.
+type Gateway struct {
+	server      socialpb.SocialServiceServer
+	interceptor grpc.UnaryServerInterceptor
+	methods     map[string]grpc.MethodHandler
+	mux         *http.ServeMux
+
+	// paths matches the request path alone; allowed lists the methods
+	// registered for each path, to answer the others with 405.
+	paths   *http.ServeMux
+	allowed map[string][]string
+}
+
+func New(server socialpb.SocialServiceServer, interceptor grpc.UnaryServerInterceptor) *Gateway {
+	g := &Gateway{
+		server:      server,
+		interceptor: interceptor,
+		methods:     make(map[string]grpc.MethodHandler),
+		mux:         http.NewServeMux(),
+		paths:       http.NewServeMux(),
+		allowed:     make(map[string][]string),
+	}
+
+	for _, method := range socialpb.SocialService_ServiceDesc.Methods {
+		g.methods[method.MethodName] = method.Handler
+	}
+
+	g.handle("POST /v1/reviews", g.route("CreateReview", func(r *http.Request, req proto.Message) error {
+		return decodeBody(r, req)
+	}))
+	g.handle("GET /v1/feed", g.route("GetFeed", func(r *http.Request, req proto.Message) error {
+		return queryInt(r, "limit", &req.(*socialpb.GetFeedRequest).Limit)
+	}))
+	g.handle("GET /v1/users/{user_id}/reviews", g.route("GetUserReviews", func(r *http.Request, req proto.Message) error {
+		in := req.(*socialpb.GetUserReviewsRequest)
+		in.UserId = r.PathValue("user_id")
+		return errors.Join(queryInt(r, "limit", &in.Limit), queryInt(r, "offset", &in.Offset))
+	}))
+	g.handle("GET /v1/games/{game_id}/reviews", g.route("GetGameReviews", func(r *http.Request, req proto.Message) error {
+		in := req.(*socialpb.GetGameReviewsRequest)
+		in.GameId = r.PathValue("game_id")
+		return errors.Join(queryInt(r, "limit", &in.Limit), queryInt(r, "offset", &in.Offset))
+	}))
+	g.handle("GET /openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(openAPI)
+	})
+
+	return g
+}
+
+// handle registers h for a "METHOD /path" pattern.
+func (g *Gateway) handle(pattern string, h http.HandlerFunc) {
+	g.mux.HandleFunc(pattern, h)
+
+	method, path, _ := strings.Cut(pattern, " ")
+	if _, ok := g.allowed[path]; !ok {
+		g.paths.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			allowed := g.allowed[path]
+			st := status.Newf(codes.Unimplemented, "method %s not allowed for %s", r.Method, r.URL.Path)
+
+			w.Header().Set("Allow", strings.Join(allowed, ", "))
+			writeStatus(w, http.StatusMethodNotAllowed, st.Message(), st)
+		})
+	}
+
+	g.allowed[path] = append(g.allowed[path], method)
+	if method == http.MethodGet {
+		g.allowed[path] = append(g.allowed[path], http.MethodHead)
+	}
+}
+
+// ServeHTTP matches the path first and the method second, so a known path
+// called with the wrong method gets 405 rather than 404.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	notAllowed, pattern := g.paths.Handler(r)
+	if pattern == "" {
+		writeError(w, status.Errorf(codes.NotFound, "no route for %s", r.URL.Path))
+		return
+	}
+
+	if _, pattern := g.mux.Handler(r); pattern == "" {
+		notAllowed.ServeHTTP(w, r)
+		return
+	}
+
+	g.mux.ServeHTTP(w, r)
+}
+
+// route serves the named RPC. parse fills the request message, which is
+// allocated by the generated method handler, from the HTTP request.
+func (g *Gateway) route(method string, parse func(r *http.Request, req proto.Message) error) http.HandlerFunc {
+	handler := g.methods[method]
+	fullMethod := "/" + socialpb.SocialService_ServiceDesc.ServiceName + "/" + method
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		stream := &transportStream{method: fullMethod, header: metadata.MD{}}
+		ctx := grpc.NewContextWithServerTransportStream(incomingContext(r), stream)
+
+		resp, err := handler(g.server, ctx, func(in any) error {
+			if err := parse(r, in.(proto.Message)); err != nil {
+				return status.Error(codes.InvalidArgument, err.Error())
+			}
+			return nil
+		}, g.interceptor)
+
+		for key, values := range stream.header {
+			for _, value := range values {
+				w.Header().Add(key, value)
+			}
+		}
+
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		body, err := marshaler.Marshal(resp.(proto.Message))
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("method", method).Msg("gateway: failed to encode response")
+			writeError(w, status.Error(codes.Internal, "failed to encode response"))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		if method == "CreateReview" {
+			w.WriteHeader(http.StatusCreated)
+		}
+		_, _ = w.Write(body)
+	}
+}
+
+// incomingContext carries the forwarded headers and the client address the
+// way a gRPC server would present them.
+func incomingContext(r *http.Request) context.Context {
+	md := metadata.MD{}
+	for _, key := range forwardedHeaders {
+		if values := r.Header.Values(key); len(values) > 0 {
+			md[key] = values
+		}
+	}
+
+	ctx := metadata.NewIncomingContext(r.Context(), md)
+
+	if addr, err := net.ResolveTCPAddr("tcp", r.RemoteAddr); err == nil {
+		ctx = peer.NewContext(ctx, &peer.Peer{Addr: addr})
+	}
+
+	return ctx
+}
+
+func decodeBody(r *http.Request, req proto.Message) error {
+	body, err := io.ReadAll(http.MaxBytesReader(nil, r.Body, maxBodySize))
+	if err != nil {
+		return errors.New("failed to read request body")
+	}
+
+	if err := protojson.Unmarshal(body, req); err != nil {
+		return errors.New("request body is not a valid JSON " + string(req.ProtoReflect().Descriptor().Name()))
+	}
+
+	return nil
+}
+
+// queryInt sets *dst from the query parameter when present.
+func queryInt(r *http.Request, name string, dst *int32) error {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil
+	}
+
+	n, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 32)
+	if err != nil {
+		return errors.New(name + " must be an integer")
+	}
+
+	*dst = int32(n)
+	return nil
+}
+
+// transportStream collects the headers handlers set with grpc.SetHeader so
+// they can be returned as HTTP headers.
+type transportStream struct {
+	method string
+	header metadata.MD
+}
+
+func (s *transportStream) Method() string {
+	return s.method
+}
+
+func (s *transportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *transportStream) SendHeader(md metadata.MD) error {
+	return s.SetHeader(md)
+}
+
+func (s *transportStream) SetTrailer(metadata.MD) error {
+	return nil
+}
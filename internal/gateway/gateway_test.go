@@ -0,0 +1,269 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type fakeServer struct {
+	socialpb.UnimplementedSocialServiceServer
+	err      error
+	requests []any
+	md       metadata.MD
+}
+
+func (s *fakeServer) CreateReview(ctx context.Context, req *socialpb.CreateReviewRequest) (*socialpb.CreateReviewResponse, error) {
+	s.requests = append(s.requests, req)
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs("etag", `"1"`))
+
+	return &socialpb.CreateReviewResponse{Review: &socialpb.Review{Id: "r1", GameId: req.GameId, Rating: req.Rating}}, nil
+}
+
+func (s *fakeServer) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest) (*socialpb.GetFeedResponse, error) {
+	s.requests = append(s.requests, req)
+	return &socialpb.GetFeedResponse{}, s.err
+}
+
+func (s *fakeServer) GetUserReviews(ctx context.Context, req *socialpb.GetUserReviewsRequest) (*socialpb.GetUserReviewsResponse, error) {
+	s.requests = append(s.requests, req)
+	return &socialpb.GetUserReviewsResponse{}, s.err
+}
+
+func (s *fakeServer) GetGameReviews(ctx context.Context, req *socialpb.GetGameReviewsRequest) (*socialpb.GetGameReviewsResponse, error) {
+	s.requests = append(s.requests, req)
+	return &socialpb.GetGameReviewsResponse{GameId: req.GameId}, s.err
+}
+
+func serve(t *testing.T, g *Gateway, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	rec := httptest.NewRecorder()
+	g.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func decodeError(t *testing.T, rec *httptest.ResponseRecorder) errorStatus {
+	t.Helper()
+
+	var body errorBody
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+	assert.Equal(t, rec.Code, body.Error.Code)
+
+	return body.Error
+}
+
+func TestGateway_CreateReview(t *testing.T) {
+	server := &fakeServer{}
+
+	var intercepted []string
+	g := New(server, func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		intercepted = append(intercepted, info.FullMethod)
+		return handler(ctx, req)
+	})
+
+	rec := serve(t, g, http.MethodPost, "/v1/reviews", `{"game_id": "g1", "rating": 80, "text": "ok"}`, http.Header{
+		"X-User-Id":  {"u1"},
+		"Cookie":     {"session=secret"},
+		"X-Internal": {"1"},
+	})
+
+	require.Equal(t, http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(t, []string{"/social.SocialService/CreateReview"}, intercepted)
+	assert.Equal(t, `"1"`, rec.Header().Get("Etag"))
+
+	var resp map[string]map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.Equal(t, "g1", resp["review"]["game_id"], "fields use proto names")
+	assert.Equal(t, "", resp["review"]["text"], "unset fields are emitted")
+
+	assert.Equal(t, []string{"u1"}, server.md.Get("x-user-id"))
+	assert.Empty(t, server.md.Get("cookie"), "only allowlisted headers are forwarded")
+	assert.Empty(t, server.md.Get("x-internal"))
+}
+
+func TestGateway_Routes(t *testing.T) {
+	server := &fakeServer{}
+	g := New(server, nil)
+
+	for _, target := range []string{
+		"/v1/feed?limit=5",
+		"/v1/users/u1/reviews?limit=10&offset=20",
+		"/v1/games/g1/reviews?offset=3",
+	} {
+		rec := serve(t, g, http.MethodGet, target, "", nil)
+		assert.Equal(t, http.StatusOK, rec.Code, target)
+	}
+
+	require.Len(t, server.requests, 3)
+	assert.Equal(t, int32(5), server.requests[0].(*socialpb.GetFeedRequest).Limit)
+	assert.Equal(t, &socialpb.GetUserReviewsRequest{UserId: "u1", Limit: 10, Offset: 20}, stripped(server.requests[1]))
+	assert.Equal(t, &socialpb.GetGameReviewsRequest{GameId: "g1", Offset: 3}, stripped(server.requests[2]))
+}
+
+// stripped copies the exported fields so requests compare with assert.Equal.
+func stripped(req any) any {
+	switch req := req.(type) {
+	case *socialpb.GetUserReviewsRequest:
+		return &socialpb.GetUserReviewsRequest{UserId: req.UserId, Limit: req.Limit, Offset: req.Offset}
+	case *socialpb.GetGameReviewsRequest:
+		return &socialpb.GetGameReviewsRequest{GameId: req.GameId, Limit: req.Limit, Offset: req.Offset}
+	}
+	return req
+}
+
+func TestGateway_Errors(t *testing.T) {
+	badRequest, err := status.New(codes.InvalidArgument, "invalid game_id: must be a UUID").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{{Field: "game_id", Description: "must be a UUID"}},
+	})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		err         error
+		method      string
+		target      string
+		body        string
+		wantCode    int
+		wantStatus  string
+		wantMessage string
+		wantDetails int
+		wantAllow   string
+	}{
+		{
+			name:        "bad request keeps details",
+			err:         badRequest.Err(),
+			wantCode:    http.StatusBadRequest,
+			wantStatus:  "INVALID_ARGUMENT",
+			wantMessage: "invalid game_id: must be a UUID",
+			wantDetails: 1,
+		},
+		{
+			name:        "failed precondition is the caller's",
+			err:         status.Error(codes.FailedPrecondition, "game has not been released yet"),
+			wantCode:    http.StatusBadRequest,
+			wantStatus:  "FAILED_PRECONDITION",
+			wantMessage: "game has not been released yet",
+		},
+		{
+			name:        "conflict",
+			err:         status.Error(codes.AlreadyExists, "review already exists"),
+			wantCode:    http.StatusConflict,
+			wantStatus:  "ALREADY_EXISTS",
+			wantMessage: "review already exists",
+		},
+		{
+			name:        "internal hides the message",
+			err:         status.Error(codes.Internal, "pq: connection refused"),
+			wantCode:    http.StatusInternalServerError,
+			wantStatus:  "INTERNAL",
+			wantMessage: "internal server error",
+		},
+		{
+			name:       "malformed body",
+			body:       `{"rating": "high"`,
+			wantCode:   http.StatusBadRequest,
+			wantStatus: "INVALID_ARGUMENT",
+		},
+		{
+			name:       "malformed query",
+			method:     http.MethodGet,
+			target:     "/v1/feed?limit=ten",
+			wantCode:   http.StatusBadRequest,
+			wantStatus: "INVALID_ARGUMENT",
+		},
+		{
+			name:       "unknown route",
+			method:     http.MethodGet,
+			target:     "/v1/unknown",
+			wantCode:   http.StatusNotFound,
+			wantStatus: "NOT_FOUND",
+		},
+		{
+			name:       "wrong method",
+			method:     http.MethodDelete,
+			target:     "/v1/reviews",
+			wantCode:   http.StatusMethodNotAllowed,
+			wantStatus: "UNIMPLEMENTED",
+			wantAllow:  "POST",
+		},
+		{
+			name:       "wrong method on path with wildcard",
+			method:     http.MethodPost,
+			target:     "/v1/games/g1/reviews",
+			wantCode:   http.StatusMethodNotAllowed,
+			wantStatus: "UNIMPLEMENTED",
+			wantAllow:  "GET, HEAD",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := New(&fakeServer{err: tt.err}, nil)
+
+			method, target, body := http.MethodPost, "/v1/reviews", `{"game_id": "g1"}`
+			if tt.method != "" {
+				method, target, body = tt.method, tt.target, ""
+			}
+			if tt.body != "" {
+				body = tt.body
+			}
+
+			rec := serve(t, g, method, target, body, nil)
+			assert.Equal(t, tt.wantCode, rec.Code)
+			assert.Equal(t, tt.wantAllow, rec.Header().Get("Allow"))
+
+			got := decodeError(t, rec)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			if tt.wantMessage != "" {
+				assert.Equal(t, tt.wantMessage, got.Message)
+			}
+			assert.Len(t, got.Details, tt.wantDetails)
+		})
+	}
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	rec := serve(t, New(&fakeServer{}, nil), http.MethodGet, "/openapi.json", "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var doc struct {
+		OpenAPI string                    `json:"openapi"`
+		Paths   map[string]map[string]any `json:"paths"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	for path, method := range map[string]string{
+		"/v1/reviews":                 "post",
+		"/v1/feed":                    "get",
+		"/v1/users/{user_id}/reviews": "get",
+		"/v1/games/{game_id}/reviews": "get",
+	} {
+		assert.Contains(t, doc.Paths[path], method, path)
+	}
+}
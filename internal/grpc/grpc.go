@@ -1,27 +1,192 @@
 package grpc
 
 import (
+	"context"
 	"database/sql"
+	"net/http"
+	moderationpb "social-service/gen/go/moderation"
+	reviewspb "social-service/gen/go/reviews"
+	"social-service/internal/config"
+	"social-service/internal/feed"
+	"social-service/internal/gateway"
 	"social-service/internal/handlers"
+	"social-service/internal/idempotency"
+	"social-service/internal/metrics"
+	"social-service/internal/middleware"
 	"social-service/internal/producer"
+	"social-service/internal/ratelimit"
+	"social-service/internal/reviewbomb"
 	"social-service/internal/service"
 	"social-service/internal/storage"
+	"social-service/internal/validation"
 
-	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
+	"github.com/google/uuid"
 	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
-var GamesClient gamepb.GameServiceClient
+// Dependencies are the external resources the server is built on. They are
+// owned by the caller, which also closes them.
+type Dependencies struct {
+	DB             *sql.DB
+	RatingProducer producer.RatingPublisher
+	AppealProducer producer.AppealPublisher
+	Health         healthpb.HealthServer
+	Users          handlers.UserLookup
+	Games          handlers.GameLookup
 
-func Init(db *sql.DB, producer *producer.RatingProducer) *grpc.Server {
-	s := grpc.NewServer()
+	// Credentials secures the listener; nil serves plaintext.
+	Credentials credentials.TransportCredentials
 
-	socialRepo := storage.NewReviewRepo(db)
-	socialService := service.NewReviewService(socialRepo)
-	socialHandler := handlers.NewReviewHandler(socialService, producer)
+	// Tokens verifies bearer tokens; nil trusts the identity headers set by
+	// the gateway.
+	Tokens middleware.TokenVerifier
+
+	// Idempotency keeps responses to writes sent with an idempotency key;
+	// nil keeps them in memory, which only covers retries to this instance.
+	Idempotency idempotency.Store
+
+	// Detector flags review bombs; the caller runs it. Nil disables detection.
+	Detector *reviewbomb.Detector
+}
+
+// idempotentMethods are the writes that honour the idempotency-key header.
+var idempotentMethods = []string{
+	"CreateReview",
+	"UpdateReview",
+	"SetShadowBan",
+	"HideReview",
+	"DeleteReview",
+	"RestoreReview",
+	"FileAppeal",
+	"ResolveAppeal",
+}
+
+// Init builds the gRPC server and the REST gateway to its SocialService.
+// Both share one interceptor chain.
+func Init(cfg *config.Config, deps Dependencies) (*grpc.Server, http.Handler) {
+	limits := make(map[string]ratelimit.Limit, len(cfg.RateLimits))
+	for method, limit := range cfg.RateLimits {
+		limits[method] = ratelimit.Limit{Rate: limit.Rate, Burst: limit.Burst}
+	}
+
+	interceptors := []grpc.UnaryServerInterceptor{
+		metrics.UnaryServerInterceptor(),
+		middleware.RequestIDInterceptor(),
+		middleware.AccessLogInterceptor(),
+		middleware.RecoveryInterceptor(),
+	}
+	// Authentication runs before rate limiting, which keys callers by user.
+	if deps.Tokens != nil {
+		interceptors = append(interceptors, middleware.AuthInterceptor(deps.Tokens))
+	}
+	idempotencyStore := deps.Idempotency
+	if idempotencyStore == nil {
+		idempotencyStore = idempotency.NewMemoryStore()
+	}
+
+	requestLimits := validation.Limits{
+		MaxPageSize:   cfg.MaxPageSize,
+		MaxTextLength: cfg.MaxReviewTextLength,
+	}
+
+	interceptors = append(interceptors,
+		ratelimit.UnaryServerInterceptor(ratelimit.NewMemoryStore(), limits),
+		validation.UnaryServerInterceptor(requestLimits),
+		idempotency.UnaryServerInterceptor(idempotencyStore, idempotentMethods, idempotency.Options{
+			TTL:   cfg.IdempotencyTTL,
+			Lease: cfg.IdempotencyLease,
+		}),
+	)
+
+	chain := chainUnary(interceptors)
+
+	opts := []grpc.ServerOption{
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.UnaryInterceptor(chain),
+		grpc.ChainStreamInterceptor(
+			middleware.StreamInterceptor(chain),
+			validation.StreamServerInterceptor(requestLimits),
+		),
+	}
+	if deps.Credentials != nil {
+		opts = append(opts, grpc.Creds(deps.Credentials))
+	}
+
+	s := grpc.NewServer(opts...)
+
+	moderationRepo := storage.NewModerationRepo(deps.DB)
+
+	hub := feed.NewHub(feed.Options{
+		History:        cfg.FeedHistory,
+		Buffer:         cfg.FeedBuffer,
+		MaxSubscribers: cfg.FeedMaxSubscribers,
+	})
+
+	socialRepo := storage.NewReviewRepo(deps.DB)
+	var detector service.BombDetector
+	if deps.Detector != nil {
+		detector = deps.Detector
+	}
+	socialService := service.NewReviewService(socialRepo, detector, feed.NewPublisher(hub, moderationRepo), cfg.MaxPageSize)
+	socialHandler := handlers.NewReviewHandler(socialService, deps.RatingProducer, deps.Users, deps.Games, handlers.ReviewPolicy{
+		ReleasedOnly: cfg.ReviewReleasedOnly,
+	})
+
+	reviewEditHandler := handlers.NewReviewEditHandler(socialService, deps.RatingProducer, hub)
+
+	ratingRefresher := service.NewRatingRefresher(socialService, deps.RatingProducer)
+	if deps.Detector != nil {
+		deps.Detector.OnChange(func(ctx context.Context, gameID uuid.UUID) {
+			ratingRefresher.Refresh(ctx, gameID)
+		})
+	}
+	moderationService := service.NewModerationService(moderationRepo, ratingRefresher)
+	appealService := service.NewAppealService(moderationRepo, deps.AppealProducer, ratingRefresher)
+	moderationHandler := handlers.NewModerationHandler(moderationService, appealService)
 
 	socialpb.RegisterSocialServiceServer(s, socialHandler)
+	reviewspb.RegisterReviewServiceServer(s, reviewEditHandler)
+	moderationpb.RegisterModerationServiceServer(s, moderationHandler)
+	healthpb.RegisterHealthServer(s, deps.Health)
+
+	return s, gateway.New(socialHandler, chain)
+}
+
+// chainUnary composes interceptors into one, the first being the outermost,
+// as grpc.ChainUnaryInterceptor does.
+func chainUnary(interceptors []grpc.UnaryServerInterceptor) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		next := handler
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			interceptor, inner := interceptors[i], next
+			next = func(ctx context.Context, req any) (any, error) {
+				return interceptor(ctx, req, info, inner)
+			}
+		}
+
+		return next(ctx, req)
+	}
+}
+
+// GracefulStop waits for in-flight RPCs to finish, falling back to a hard
+// Stop once ctx is done. It reports whether the drain completed in time.
+func GracefulStop(ctx context.Context, s *grpc.Server) bool {
+	done := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(done)
+	}()
 
-	return s
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		s.Stop()
+		<-done
+		return false
+	}
 }
@@ -1,11 +1,20 @@
 package grpc
 
 import (
+	"context"
+	"net"
+	"social-service/internal/config"
+	"social-service/internal/microservice"
 	"social-service/internal/producer"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 func TestInit(t *testing.T) {
@@ -16,10 +25,66 @@ func TestInit(t *testing.T) {
 		_ = db.Close()
 	}()
 
-	var testProducer *producer.RatingProducer
-
-	s := Init(db, testProducer)
+	s, gw := Init(&config.Config{}, Dependencies{
+		DB:             db,
+		RatingProducer: producer.NewRatingProducer("localhost:9092", "review_events"),
+		AppealProducer: producer.NewAppealProducer("localhost:9092", "appeal_events"),
+		Health:         health.NewServer(),
+		Users:          microservice.NewUserClient(nil),
+		Games:          microservice.NewGameClient(nil),
+	})
 
 	assert.NotNil(t, s)
+	assert.NotNil(t, gw)
 	defer s.Stop()
 }
+
+func TestGracefulStop(t *testing.T) {
+	t.Run("idle server", func(t *testing.T) {
+		s := grpc.NewServer()
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+
+		go func() { _ = s.Serve(lis) }()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+
+		assert.True(t, GracefulStop(ctx, s))
+	})
+
+	t.Run("deadline exceeded", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		s := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, _ grpc.UnaryHandler) (any, error) {
+			select {
+			case <-release:
+			case <-ctx.Done():
+			}
+			return nil, ctx.Err()
+		}))
+		healthpb.RegisterHealthServer(s, health.NewServer())
+
+		lis, err := net.Listen("tcp", "127.0.0.1:0")
+		assert.NoError(t, err)
+		go func() { _ = s.Serve(lis) }()
+
+		conn, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+		assert.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		started := make(chan struct{})
+		go func() {
+			close(started)
+			_, _ = healthpb.NewHealthClient(conn).Check(context.Background(), &healthpb.HealthCheckRequest{})
+		}()
+		<-started
+		time.Sleep(100 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		assert.False(t, GracefulStop(ctx, s))
+	})
+}
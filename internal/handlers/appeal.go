@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	moderationpb "social-service/gen/go/moderation"
+	"social-service/internal/model"
+	"social-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var appealOutcomes = map[moderationpb.AppealOutcome]string{
+	moderationpb.AppealOutcome_APPEAL_OUTCOME_UPHELD:     model.AppealStatusUpheld,
+	moderationpb.AppealOutcome_APPEAL_OUTCOME_REINSTATED: model.AppealStatusReinstated,
+}
+
+func (h *ModerationHandler) FileAppeal(ctx context.Context, req *moderationpb.FileAppealRequest) (*moderationpb.FileAppealResponse, error) {
+	userId, err := utils.GetUserID(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("ModerationHandler.FileAppeal: failed to extract user_id from context")
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	authorId, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, "invalid user_id in metadata")
+	}
+
+	reviewId, err := uuid.Parse(req.ReviewId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid review_id")
+	}
+
+	if req.Message == "" {
+		return nil, status.Error(codes.InvalidArgument, "message is required")
+	}
+
+	appeal, err := h.appeals.FileAppeal(ctx, reviewId, authorId, req.Message)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrReviewNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, model.ErrNotReviewAuthor):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, model.ErrReviewNotRemoved):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, model.ErrAppealExists):
+			return nil, status.Error(codes.AlreadyExists, err.Error())
+		}
+
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("review_id", req.ReviewId).
+			Str("user_id", userId).
+			Msg("ModerationHandler.FileAppeal: service error")
+		return nil, status.Error(codes.Internal, "failed to file appeal")
+	}
+
+	log.Ctx(ctx).Info().
+		Str("appeal_id", appeal.Id.String()).
+		Str("review_id", req.ReviewId).
+		Str("user_id", userId).
+		Msg("ModerationHandler.FileAppeal: success")
+
+	return &moderationpb.FileAppealResponse{Appeal: appealToPB(appeal)}, nil
+}
+
+func (h *ModerationHandler) ListAppeals(ctx context.Context, req *moderationpb.ListAppealsRequest) (*moderationpb.ListAppealsResponse, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("ModerationHandler.ListAppeals: access denied")
+		return nil, err
+	}
+
+	appeals, err := h.appeals.ListAppeals(ctx, req.Status, req.Limit, req.Offset)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("status", req.Status).Msg("ModerationHandler.ListAppeals: service error")
+		return nil, status.Error(codes.Internal, "failed to list appeals")
+	}
+
+	appealspb := make([]*moderationpb.Appeal, 0, len(appeals))
+	for _, appeal := range appeals {
+		appealspb = append(appealspb, appealToPB(appeal))
+	}
+
+	return &moderationpb.ListAppealsResponse{Appeals: appealspb}, nil
+}
+
+func (h *ModerationHandler) ResolveAppeal(ctx context.Context, req *moderationpb.ResolveAppealRequest) (*moderationpb.ResolveAppealResponse, error) {
+	actorId, err := requireAdmin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("ModerationHandler.ResolveAppeal: access denied")
+		return nil, err
+	}
+
+	appealId, err := uuid.Parse(req.AppealId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid appeal_id")
+	}
+
+	outcome, ok := appealOutcomes[req.Outcome]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "outcome must be upheld or reinstated")
+	}
+
+	appeal, err := h.appeals.ResolveAppeal(ctx, appealId, outcome, req.Note, actorId)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrAppealNotFound), errors.Is(err, model.ErrReviewNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, model.ErrAppealResolved):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, model.ErrSameModerator):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		}
+
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("appeal_id", req.AppealId).
+			Msg("ModerationHandler.ResolveAppeal: service error")
+		return nil, status.Error(codes.Internal, "failed to resolve appeal")
+	}
+
+	log.Ctx(ctx).Info().
+		Str("appeal_id", req.AppealId).
+		Str("actor_id", actorId.String()).
+		Str("outcome", outcome).
+		Msg("ModerationHandler.ResolveAppeal: success")
+
+	return &moderationpb.ResolveAppealResponse{Appeal: appealToPB(appeal)}, nil
+}
+
+func appealToPB(appeal *model.Appeal) *moderationpb.Appeal {
+	appealpb := &moderationpb.Appeal{
+		Id:             appeal.Id.String(),
+		ReviewId:       appeal.ReviewID.String(),
+		UserId:         appeal.UserID.String(),
+		Message:        appeal.Message,
+		Status:         appeal.Status,
+		ResolutionNote: appeal.ResolutionNote,
+		CreatedAt:      timestamppb.New(appeal.CreatedAt),
+	}
+
+	if appeal.RemovedBy != nil {
+		appealpb.RemovedBy = appeal.RemovedBy.String()
+	}
+
+	if appeal.ResolvedBy != nil {
+		appealpb.ResolvedBy = appeal.ResolvedBy.String()
+	}
+
+	if appeal.ResolvedAt != nil {
+		appealpb.ResolvedAt = timestamppb.New(*appeal.ResolvedAt)
+	}
+
+	return appealpb
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"context"
+	moderationpb "social-service/gen/go/moderation"
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var appealColumns = []string{
+	"id", "review_id", "user_id", "message", "status", "removed_by", "resolved_by",
+	"resolution_note", "created_at", "resolved_at",
+}
+
+func TestModerationHandler_FileAppeal(t *testing.T) {
+	h, dbMock, _, cleanup := setupModerationHandlerTest(t)
+	defer cleanup()
+
+	authorID, reviewID, moderatorID := uuid.New(), uuid.New(), uuid.New()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", authorID.String()))
+	now := time.Now()
+
+	reviewRow := func(userID uuid.UUID, status string) *sqlmock.Rows {
+		return sqlmock.NewRows(moderatedReviewColumns).AddRow(reviewID, userID, uuid.New(), 0, "t", now, now, 1, status)
+	}
+
+	t.Run("success", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(reviewRow(authorID, model.ReviewStatusDeleted))
+		dbMock.ExpectQuery(`SELECT actor_id FROM social.moderation_log`).
+			WithArgs(model.ModerationTargetReview, reviewID, model.ModerationActionHideReview, model.ModerationActionDeleteReview).
+			WillReturnRows(sqlmock.NewRows([]string{"actor_id"}).AddRow(moderatorID))
+		dbMock.ExpectQuery(`INSERT INTO social.review_appeals`).
+			WithArgs(reviewID, authorID, "please", &moderatorID).
+			WillReturnRows(sqlmock.NewRows(appealColumns).
+				AddRow(uuid.New(), reviewID, authorID, "please", "pending", moderatorID, nil, "", now, nil))
+		dbMock.ExpectCommit()
+
+		resp, err := h.FileAppeal(ctx, &moderationpb.FileAppealRequest{ReviewId: reviewID.String(), Message: "please"})
+		assert.NoError(t, err)
+		assert.Equal(t, "pending", resp.Appeal.Status)
+		assert.Equal(t, moderatorID.String(), resp.Appeal.RemovedBy)
+	})
+
+	t.Run("not the author", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(reviewRow(uuid.New(), model.ReviewStatusDeleted))
+		dbMock.ExpectRollback()
+
+		_, err := h.FileAppeal(ctx, &moderationpb.FileAppealRequest{ReviewId: reviewID.String(), Message: "please"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("review not removed", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(reviewRow(authorID, model.ReviewStatusPublished))
+		dbMock.ExpectRollback()
+
+		_, err := h.FileAppeal(ctx, &moderationpb.FileAppealRequest{ReviewId: reviewID.String(), Message: "please"})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("second appeal", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(reviewRow(authorID, model.ReviewStatusHidden))
+		dbMock.ExpectQuery(`SELECT actor_id`).WillReturnRows(sqlmock.NewRows([]string{"actor_id"}))
+		dbMock.ExpectQuery(`INSERT INTO social.review_appeals`).WillReturnError(&pq.Error{Code: "23505"})
+		dbMock.ExpectRollback()
+
+		_, err := h.FileAppeal(ctx, &moderationpb.FileAppealRequest{ReviewId: reviewID.String(), Message: "again"})
+		assert.Equal(t, codes.AlreadyExists, status.Code(err))
+	})
+
+	t.Run("empty message", func(t *testing.T) {
+		_, err := h.FileAppeal(ctx, &moderationpb.FileAppealRequest{ReviewId: reviewID.String()})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("anonymous caller", func(t *testing.T) {
+		_, err := h.FileAppeal(context.Background(), &moderationpb.FileAppealRequest{ReviewId: reviewID.String(), Message: "x"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestModerationHandler_ResolveAppeal(t *testing.T) {
+	h, dbMock, publisher, cleanup := setupModerationHandlerTest(t)
+	defer cleanup()
+
+	adminID, removerID := uuid.New(), uuid.New()
+	appealID, reviewID, authorID := uuid.New(), uuid.New(), uuid.New()
+	ctx := adminContext(adminID)
+	now := time.Now()
+
+	pendingRow := func(removedBy uuid.UUID) *sqlmock.Rows {
+		return sqlmock.NewRows(appealColumns).
+			AddRow(appealID, reviewID, authorID, "please", "pending", removedBy, nil, "", now, nil)
+	}
+
+	t.Run("reinstated", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FROM social.review_appeals WHERE id = \$1 FOR UPDATE`).WithArgs(appealID).WillReturnRows(pendingRow(removerID))
+		dbMock.ExpectQuery(`FROM social.reviews WHERE id = \$1 FOR UPDATE`).
+			WillReturnRows(sqlmock.NewRows(moderatedReviewColumns).AddRow(reviewID, authorID, uuid.New(), 0, "t", now, now, 1, "deleted"))
+		dbMock.ExpectQuery(`UPDATE social.reviews`).
+			WithArgs(reviewID, model.ReviewStatusPublished).
+			WillReturnRows(sqlmock.NewRows(moderatedReviewColumns).AddRow(reviewID, authorID, uuid.New(), 0, "t", now, now, 1, "published"))
+		dbMock.ExpectQuery(`INSERT INTO social.moderation_log`).
+			WithArgs(adminID, model.ModerationActionRestoreReview, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(logEntryRow())
+		dbMock.ExpectQuery(`UPDATE social.review_appeals`).
+			WithArgs(appealID, model.AppealStatusReinstated, adminID, "fair").
+			WillReturnRows(sqlmock.NewRows(appealColumns).
+				AddRow(appealID, reviewID, authorID, "please", "reinstated", removerID, adminID, "fair", now, now))
+		dbMock.ExpectQuery(`INSERT INTO social.moderation_log`).
+			WithArgs(adminID, model.ModerationActionResolveAppeal, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(logEntryRow())
+		dbMock.ExpectCommit()
+		publisher.On("PublishResolution", mock.Anything, mock.MatchedBy(func(a *model.Appeal) bool {
+			return a.Id == appealID && a.Status == model.AppealStatusReinstated
+		})).Return(nil).Once()
+
+		resp, err := h.ResolveAppeal(ctx, &moderationpb.ResolveAppealRequest{
+			AppealId: appealID.String(),
+			Outcome:  moderationpb.AppealOutcome_APPEAL_OUTCOME_REINSTATED,
+			Note:     "fair",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "reinstated", resp.Appeal.Status)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+		publisher.AssertExpectations(t)
+	})
+
+	t.Run("same moderator", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FROM social.review_appeals`).WillReturnRows(pendingRow(adminID))
+		dbMock.ExpectRollback()
+
+		_, err := h.ResolveAppeal(ctx, &moderationpb.ResolveAppealRequest{
+			AppealId: appealID.String(),
+			Outcome:  moderationpb.AppealOutcome_APPEAL_OUTCOME_UPHELD,
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("already resolved", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FROM social.review_appeals`).WillReturnRows(sqlmock.NewRows(appealColumns).
+			AddRow(appealID, reviewID, authorID, "please", "upheld", removerID, uuid.New(), "", now, now))
+		dbMock.ExpectRollback()
+
+		_, err := h.ResolveAppeal(ctx, &moderationpb.ResolveAppealRequest{
+			AppealId: appealID.String(),
+			Outcome:  moderationpb.AppealOutcome_APPEAL_OUTCOME_UPHELD,
+		})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("unspecified outcome", func(t *testing.T) {
+		_, err := h.ResolveAppeal(ctx, &moderationpb.ResolveAppealRequest{AppealId: appealID.String()})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("not an admin", func(t *testing.T) {
+		_, err := h.ResolveAppeal(context.Background(), &moderationpb.ResolveAppealRequest{AppealId: appealID.String()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestModerationHandler_ListAppeals(t *testing.T) {
+	h, dbMock, _, cleanup := setupModerationHandlerTest(t)
+	defer cleanup()
+
+	ctx := adminContext(uuid.New())
+
+	dbMock.ExpectQuery(`FROM social.review_appeals`).
+		WithArgs("pending", 50, 0).
+		WillReturnRows(sqlmock.NewRows(appealColumns).
+			AddRow(uuid.New(), uuid.New(), uuid.New(), "m", "pending", nil, nil, "", time.Now(), nil))
+
+	resp, err := h.ListAppeals(ctx, &moderationpb.ListAppealsRequest{Status: "pending"})
+	assert.NoError(t, err)
+	assert.Len(t, resp.Appeals, 1)
+	assert.Empty(t, resp.Appeals[0].RemovedBy)
+}
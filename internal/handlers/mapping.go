@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"context"
+	"social-service/internal/model"
+	"strings"
+
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ReadMaskHeader carries a comma-separated list of socialpb.Review field
+// names, e.g. "id,rating,game_id". Listing RPCs return only those fields.
+const ReadMaskHeader = "x-read-mask"
+
+func reviewToPB(review *model.Review) *socialpb.Review {
+	return &socialpb.Review{
+		Id:        review.Id.String(),
+		UserId:    review.UserID.String(),
+		GameId:    review.GameID.String(),
+		Rating:    int32(review.Rating),
+		Text:      review.Text,
+		CreatedAt: timestamppb.New(review.CreatedAt),
+		UpdatedAt: timestamppb.New(review.UpdatedAt),
+	}
+}
+
+// reviewsToPB maps reviews and strips every field outside mask. A nil mask
+// keeps all fields.
+func reviewsToPB(reviews []*model.Review, mask *fieldmaskpb.FieldMask) []*socialpb.Review {
+	keep := maskedFields(mask)
+
+	revpb := make([]*socialpb.Review, 0, len(reviews))
+	for _, review := range reviews {
+		pb := reviewToPB(review)
+		if keep != nil {
+			applyMask(pb.ProtoReflect(), keep)
+		}
+		revpb = append(revpb, pb)
+	}
+
+	return revpb
+}
+
+// readMask parses the read mask sent in metadata. It returns nil when the
+// caller did not send one and InvalidArgument for unknown fields.
+func readMask(ctx context.Context) (*fieldmaskpb.FieldMask, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, nil
+	}
+
+	values := md.Get(ReadMaskHeader)
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	var paths []string
+	for _, value := range values {
+		for _, path := range strings.Split(value, ",") {
+			if path = strings.TrimSpace(path); path != "" {
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	mask, err := fieldmaskpb.New(&socialpb.Review{}, paths...)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid "+ReadMaskHeader+": "+err.Error())
+	}
+
+	return mask, nil
+}
+
+func maskedFields(mask *fieldmaskpb.FieldMask) map[protoreflect.Name]bool {
+	if mask == nil {
+		return nil
+	}
+
+	keep := make(map[protoreflect.Name]bool, len(mask.Paths))
+	for _, path := range mask.Paths {
+		field, _, _ := strings.Cut(path, ".")
+		keep[protoreflect.Name(field)] = true
+	}
+
+	return keep
+}
+
+func applyMask(msg protoreflect.Message, keep map[protoreflect.Name]bool) {
+	msg.Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		if !keep[fd.Name()] {
+			msg.Clear(fd)
+		}
+		return true
+	})
+}
@@ -0,0 +1,275 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	moderationpb "social-service/gen/go/moderation"
+	"social-service/internal/model"
+	"social-service/internal/service"
+	"social-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const adminRole = "admin"
+
+type ModerationHandler struct {
+	moderationpb.UnimplementedModerationServiceServer
+	service *service.ModerationService
+	appeals *service.AppealService
+}
+
+func NewModerationHandler(service *service.ModerationService, appeals *service.AppealService) *ModerationHandler {
+	return &ModerationHandler{
+		service: service,
+		appeals: appeals,
+	}
+}
+
+func (h *ModerationHandler) SetShadowBan(ctx context.Context, req *moderationpb.SetShadowBanRequest) (*moderationpb.SetShadowBanResponse, error) {
+	actorId, err := requireAdmin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("ModerationHandler.SetShadowBan: access denied")
+		return nil, err
+	}
+
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	ban, err := h.service.SetShadowBan(ctx, userId, req.Banned, req.Reason, actorId)
+	if err != nil {
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", req.UserId).
+			Bool("banned", req.Banned).
+			Msg("ModerationHandler.SetShadowBan: service error")
+		return nil, status.Error(codes.Internal, "failed to update shadow ban")
+	}
+
+	log.Ctx(ctx).Info().
+		Str("user_id", req.UserId).
+		Str("actor_id", actorId.String()).
+		Bool("banned", req.Banned).
+		Msg("ModerationHandler.SetShadowBan: success")
+
+	return &moderationpb.SetShadowBanResponse{ShadowBan: shadowBanToPB(userId, ban)}, nil
+}
+
+func (h *ModerationHandler) GetShadowBan(ctx context.Context, req *moderationpb.GetShadowBanRequest) (*moderationpb.GetShadowBanResponse, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("ModerationHandler.GetShadowBan: access denied")
+		return nil, err
+	}
+
+	userId, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid user_id")
+	}
+
+	ban, err := h.service.GetShadowBan(ctx, userId)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("user_id", req.UserId).Msg("ModerationHandler.GetShadowBan: service error")
+		return nil, status.Error(codes.Internal, "failed to get shadow ban")
+	}
+
+	return &moderationpb.GetShadowBanResponse{ShadowBan: shadowBanToPB(userId, ban)}, nil
+}
+
+func (h *ModerationHandler) HideReview(ctx context.Context, req *moderationpb.ModerateReviewRequest) (*moderationpb.ModerateReviewResponse, error) {
+	return h.moderateReview(ctx, req, model.ModerationActionHideReview)
+}
+
+func (h *ModerationHandler) DeleteReview(ctx context.Context, req *moderationpb.ModerateReviewRequest) (*moderationpb.ModerateReviewResponse, error) {
+	return h.moderateReview(ctx, req, model.ModerationActionDeleteReview)
+}
+
+func (h *ModerationHandler) RestoreReview(ctx context.Context, req *moderationpb.ModerateReviewRequest) (*moderationpb.ModerateReviewResponse, error) {
+	return h.moderateReview(ctx, req, model.ModerationActionRestoreReview)
+}
+
+func (h *ModerationHandler) moderateReview(ctx context.Context, req *moderationpb.ModerateReviewRequest, action string) (*moderationpb.ModerateReviewResponse, error) {
+	actorId, err := requireAdmin(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Str("action", action).Msg("ModerationHandler.ModerateReview: access denied")
+		return nil, err
+	}
+
+	reviewId, err := uuid.Parse(req.ReviewId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid review_id")
+	}
+
+	review, err := h.service.ModerateReview(ctx, reviewId, action, req.Reason, actorId)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrReviewNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, model.ErrReviewStatusUnchanged):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
+
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("review_id", req.ReviewId).
+			Str("action", action).
+			Msg("ModerationHandler.ModerateReview: service error")
+		return nil, status.Error(codes.Internal, "failed to moderate review")
+	}
+
+	log.Ctx(ctx).Info().
+		Str("review_id", req.ReviewId).
+		Str("actor_id", actorId.String()).
+		Str("action", action).
+		Msg("ModerationHandler.ModerateReview: success")
+
+	return &moderationpb.ModerateReviewResponse{Review: moderatedReviewToPB(review)}, nil
+}
+
+func (h *ModerationHandler) ListModerationLog(ctx context.Context, req *moderationpb.ListModerationLogRequest) (*moderationpb.ListModerationLogResponse, error) {
+	if _, err := requireAdmin(ctx); err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("ModerationHandler.ListModerationLog: access denied")
+		return nil, err
+	}
+
+	filter := &model.ModerationLogFilter{
+		TargetType: req.TargetType,
+		Action:     req.Action,
+		Limit:      req.Limit,
+		Offset:     req.Offset,
+	}
+
+	if req.ActorId != "" {
+		actorId, err := uuid.Parse(req.ActorId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid actor_id")
+		}
+		filter.ActorID = &actorId
+	}
+
+	if req.TargetId != "" {
+		targetId, err := uuid.Parse(req.TargetId)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid target_id")
+		}
+		filter.TargetID = &targetId
+	}
+
+	if req.From != nil {
+		from := req.From.AsTime()
+		filter.From = &from
+	}
+
+	if req.To != nil {
+		to := req.To.AsTime()
+		filter.To = &to
+	}
+
+	entries, err := h.service.ListLog(ctx, filter)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("ModerationHandler.ListModerationLog: service error")
+		return nil, status.Error(codes.Internal, "failed to list moderation log")
+	}
+
+	entriespb := make([]*moderationpb.ModerationLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		entrypb, err := logEntryToPB(entry)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("entry_id", entry.Id.String()).Msg("ModerationHandler.ListModerationLog: malformed snapshot")
+			return nil, status.Error(codes.Internal, "failed to list moderation log")
+		}
+		entriespb = append(entriespb, entrypb)
+	}
+
+	return &moderationpb.ListModerationLogResponse{Entries: entriespb}, nil
+}
+
+// requireAdmin returns the caller's ID if the gateway marked them as admin.
+func requireAdmin(ctx context.Context) (uuid.UUID, error) {
+	userId, err := utils.GetUserID(ctx)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	role, err := utils.GetUserRole(ctx)
+	if err != nil || role != adminRole {
+		return uuid.Nil, status.Error(codes.PermissionDenied, "admin role required")
+	}
+
+	actorId, err := uuid.Parse(userId)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.PermissionDenied, "invalid user_id in metadata")
+	}
+
+	return actorId, nil
+}
+
+func shadowBanToPB(userId uuid.UUID, ban *model.ShadowBan) *moderationpb.ShadowBan {
+	if ban == nil {
+		return &moderationpb.ShadowBan{UserId: userId.String()}
+	}
+
+	return &moderationpb.ShadowBan{
+		UserId:    ban.UserID.String(),
+		Banned:    true,
+		Reason:    ban.Reason,
+		BannedBy:  ban.BannedBy.String(),
+		CreatedAt: timestamppb.New(ban.CreatedAt),
+	}
+}
+
+func moderatedReviewToPB(review *model.ModeratedReview) *moderationpb.ModeratedReview {
+	return &moderationpb.ModeratedReview{
+		Id:        review.Id.String(),
+		UserId:    review.UserID.String(),
+		GameId:    review.GameID.String(),
+		Rating:    int32(review.Rating),
+		Text:      review.Text,
+		Status:    review.Status,
+		CreatedAt: timestamppb.New(review.CreatedAt),
+		UpdatedAt: timestamppb.New(review.UpdatedAt),
+	}
+}
+
+func logEntryToPB(entry *model.ModerationLogEntry) (*moderationpb.ModerationLogEntry, error) {
+	before, err := snapshotToPB(entry.Before)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := snapshotToPB(entry.After)
+	if err != nil {
+		return nil, err
+	}
+
+	return &moderationpb.ModerationLogEntry{
+		Id:         entry.Id.String(),
+		ActorId:    entry.ActorID.String(),
+		Action:     entry.Action,
+		TargetType: entry.TargetType,
+		TargetId:   entry.TargetID.String(),
+		Reason:     entry.Reason,
+		Before:     before,
+		After:      after,
+		CreatedAt:  timestamppb.New(entry.CreatedAt),
+	}, nil
+}
+
+func snapshotToPB(raw []byte) (*structpb.Struct, error) {
+	if len(raw) == 0 {
+		return nil, nil
+	}
+
+	snapshot := &structpb.Struct{}
+	if err := snapshot.UnmarshalJSON(raw); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
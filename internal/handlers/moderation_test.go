@@ -0,0 +1,234 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	moderationpb "social-service/gen/go/moderation"
+	"social-service/internal/model"
+	"social-service/internal/service"
+	"social-service/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+type MockAppealPublisher struct {
+	mock.Mock
+}
+
+func (m *MockAppealPublisher) PublishResolution(ctx context.Context, appeal *model.Appeal) error {
+	args := m.Called(ctx, appeal)
+	return args.Error(0)
+}
+
+func setupModerationHandlerTest(t *testing.T) (*ModerationHandler, sqlmock.Sqlmock, *MockAppealPublisher, func()) {
+	db, dbMock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	repo := storage.NewModerationRepo(db)
+	publisher := new(MockAppealPublisher)
+	h := NewModerationHandler(service.NewModerationService(repo, nil), service.NewAppealService(repo, publisher, nil))
+
+	return h, dbMock, publisher, func() {
+		dbMock.ExpectClose()
+		_ = db.Close()
+	}
+}
+
+var (
+	shadowBanColumns       = []string{"user_id", "reason", "banned_by", "created_at"}
+	moderatedReviewColumns = []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version", "status"}
+	logEntryColumns        = []string{"id", "actor_id", "action", "target_type", "target_id", "reason", "before", "after", "created_at"}
+)
+
+func logEntryRow() *sqlmock.Rows {
+	return sqlmock.NewRows(logEntryColumns).
+		AddRow(uuid.New(), uuid.New(), "", "", uuid.New(), "", nil, nil, time.Now())
+}
+
+func adminContext(adminID uuid.UUID) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(
+		"x-user-id", adminID.String(),
+		"x-user-role", "admin",
+	))
+}
+
+func TestModerationHandler_SetShadowBan(t *testing.T) {
+	h, dbMock, _, cleanup := setupModerationHandlerTest(t)
+	defer cleanup()
+
+	adminID := uuid.New()
+	userID := uuid.New()
+	ctx := adminContext(adminID)
+
+	t.Run("not an admin", func(t *testing.T) {
+		userCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", uuid.NewString(), "x-user-role", "user"))
+		_, err := h.SetShadowBan(userCtx, &moderationpb.SetShadowBanRequest{UserId: userID.String(), Banned: true})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("no metadata", func(t *testing.T) {
+		_, err := h.SetShadowBan(context.Background(), &moderationpb.SetShadowBanRequest{UserId: userID.String(), Banned: true})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		_, err := h.SetShadowBan(ctx, &moderationpb.SetShadowBanRequest{UserId: "nope", Banned: true})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("ban", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FROM social.shadow_bans`).WillReturnRows(sqlmock.NewRows(shadowBanColumns))
+		dbMock.ExpectQuery(`INSERT INTO social.shadow_bans`).
+			WithArgs(userID, "spam", adminID).
+			WillReturnRows(sqlmock.NewRows(shadowBanColumns).AddRow(userID, "spam", adminID, time.Now()))
+		dbMock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnRows(logEntryRow())
+		dbMock.ExpectCommit()
+
+		resp, err := h.SetShadowBan(ctx, &moderationpb.SetShadowBanRequest{UserId: userID.String(), Banned: true, Reason: "spam"})
+		assert.NoError(t, err)
+		assert.True(t, resp.ShadowBan.Banned)
+		assert.Equal(t, adminID.String(), resp.ShadowBan.BannedBy)
+	})
+
+	t.Run("unban", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FROM social.shadow_bans`).WillReturnRows(sqlmock.NewRows(shadowBanColumns))
+		dbMock.ExpectExec(`DELETE FROM social.shadow_bans`).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+		dbMock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnRows(logEntryRow())
+		dbMock.ExpectCommit()
+
+		resp, err := h.SetShadowBan(ctx, &moderationpb.SetShadowBanRequest{UserId: userID.String(), Banned: false})
+		assert.NoError(t, err)
+		assert.False(t, resp.ShadowBan.Banned)
+		assert.Equal(t, userID.String(), resp.ShadowBan.UserId)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		dbMock.ExpectBegin().WillReturnError(errors.New("db fail"))
+		_, err := h.SetShadowBan(ctx, &moderationpb.SetShadowBanRequest{UserId: userID.String(), Banned: true})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestModerationHandler_GetShadowBan(t *testing.T) {
+	h, dbMock, _, cleanup := setupModerationHandlerTest(t)
+	defer cleanup()
+
+	ctx := adminContext(uuid.New())
+	userID := uuid.New()
+
+	t.Run("not banned", func(t *testing.T) {
+		dbMock.ExpectQuery(`FROM social.shadow_bans`).WillReturnRows(sqlmock.NewRows(shadowBanColumns))
+
+		resp, err := h.GetShadowBan(ctx, &moderationpb.GetShadowBanRequest{UserId: userID.String()})
+		assert.NoError(t, err)
+		assert.False(t, resp.ShadowBan.Banned)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		dbMock.ExpectQuery(`FROM social.shadow_bans`).WillReturnError(errors.New("db fail"))
+		_, err := h.GetShadowBan(ctx, &moderationpb.GetShadowBanRequest{UserId: userID.String()})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+}
+
+func TestModerationHandler_ModerateReview(t *testing.T) {
+	h, dbMock, _, cleanup := setupModerationHandlerTest(t)
+	defer cleanup()
+
+	ctx := adminContext(uuid.New())
+	reviewID := uuid.New()
+	now := time.Now()
+
+	t.Run("delete", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(sqlmock.NewRows(moderatedReviewColumns).
+			AddRow(reviewID, uuid.New(), uuid.New(), 0, "t", now, now, 1, "published"))
+		dbMock.ExpectQuery(`UPDATE social.reviews`).
+			WithArgs(reviewID, "deleted").
+			WillReturnRows(sqlmock.NewRows(moderatedReviewColumns).AddRow(reviewID, uuid.New(), uuid.New(), 0, "t", now, now, 1, "deleted"))
+		dbMock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnRows(logEntryRow())
+		dbMock.ExpectCommit()
+
+		resp, err := h.DeleteReview(ctx, &moderationpb.ModerateReviewRequest{ReviewId: reviewID.String(), Reason: "spam"})
+		assert.NoError(t, err)
+		assert.Equal(t, "deleted", resp.Review.Status)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(sqlmock.NewRows(moderatedReviewColumns))
+		dbMock.ExpectRollback()
+
+		_, err := h.HideReview(ctx, &moderationpb.ModerateReviewRequest{ReviewId: reviewID.String()})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("already published", func(t *testing.T) {
+		dbMock.ExpectBegin()
+		dbMock.ExpectQuery(`FOR UPDATE`).WillReturnRows(sqlmock.NewRows(moderatedReviewColumns).
+			AddRow(reviewID, uuid.New(), uuid.New(), 0, "t", now, now, 1, "published"))
+		dbMock.ExpectRollback()
+
+		_, err := h.RestoreReview(ctx, &moderationpb.ModerateReviewRequest{ReviewId: reviewID.String()})
+		assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+	})
+
+	t.Run("invalid review id", func(t *testing.T) {
+		_, err := h.HideReview(ctx, &moderationpb.ModerateReviewRequest{ReviewId: "bad"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("not an admin", func(t *testing.T) {
+		_, err := h.HideReview(context.Background(), &moderationpb.ModerateReviewRequest{ReviewId: reviewID.String()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestModerationHandler_ListModerationLog(t *testing.T) {
+	h, dbMock, _, cleanup := setupModerationHandlerTest(t)
+	defer cleanup()
+
+	ctx := adminContext(uuid.New())
+	targetID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		dbMock.ExpectQuery(`WHERE target_id = \$1`).
+			WithArgs(targetID, 50, 0).
+			WillReturnRows(sqlmock.NewRows(logEntryColumns).
+				AddRow(uuid.New(), uuid.New(), "hide_review", "review", targetID, "spam",
+					[]byte(`{"status":"published"}`), []byte(`{"status":"hidden"}`), time.Now()))
+
+		resp, err := h.ListModerationLog(ctx, &moderationpb.ListModerationLogRequest{TargetId: targetID.String()})
+		assert.NoError(t, err)
+		require.Len(t, resp.Entries, 1)
+		assert.Equal(t, "published", resp.Entries[0].Before.Fields["status"].GetStringValue())
+		assert.Equal(t, "hidden", resp.Entries[0].After.Fields["status"].GetStringValue())
+	})
+
+	t.Run("invalid actor id", func(t *testing.T) {
+		_, err := h.ListModerationLog(ctx, &moderationpb.ListModerationLogRequest{ActorId: "bad"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		dbMock.ExpectQuery(`FROM social.moderation_log`).WillReturnError(errors.New("db fail"))
+		_, err := h.ListModerationLog(ctx, &moderationpb.ListModerationLogRequest{})
+		assert.Equal(t, codes.Internal, status.Code(err))
+	})
+
+	t.Run("not an admin", func(t *testing.T) {
+		_, err := h.ListModerationLog(context.Background(), &moderationpb.ListModerationLogRequest{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
@@ -0,0 +1,44 @@
+package handlers
+
+import (
+	"strconv"
+	"time"
+
+	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
+)
+
+// ReviewPolicy restricts which games may be reviewed.
+type ReviewPolicy struct {
+	// ReleasedOnly rejects reviews of games whose first release date is in
+	// the future or unknown.
+	ReleasedOnly bool
+}
+
+// releaseDateLayouts are tried in order; games-service has sent both full
+// timestamps and plain dates.
+var releaseDateLayouts = []string{time.RFC3339, time.DateOnly}
+
+// released reports whether game came out before now.
+func released(game *gamepb.Game, now time.Time) bool {
+	date, ok := parseReleaseDate(game.GetFirstReleaseDate())
+	return ok && !date.After(now)
+}
+
+// parseReleaseDate accepts the layouts above or Unix seconds.
+func parseReleaseDate(value string) (time.Time, bool) {
+	if value == "" {
+		return time.Time{}, false
+	}
+
+	for _, layout := range releaseDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), true
+	}
+
+	return time.Time{}, false
+}
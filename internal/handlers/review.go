@@ -3,60 +3,101 @@ package handlers
 import (
 	"context"
 	"errors"
-	"social-service/internal/microservice"
 	"social-service/internal/producer"
 	"social-service/internal/service"
 	"social-service/internal/utils"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/rs/zerolog/log"
 	authpb "github.com/viktoralyoshin/playhub-proto/gen/go/auth"
 	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
 	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
 	"github.com/viktoralyoshin/utils/pkg/errs"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// UserLookup resolves users owned by auth-service.
+type UserLookup interface {
+	GetUser(ctx context.Context, userID string) (*authpb.GetUserResponse, error)
+}
+
+// GameLookup resolves games owned by games-service.
+type GameLookup interface {
+	GetGame(ctx context.Context, gameID string) (*gamepb.Game, error)
+}
+
 type ReviewHandler struct {
 	socialpb.UnimplementedSocialServiceServer
 	service  *service.ReviewService
 	producer producer.RatingPublisher
+	users    UserLookup
+	games    GameLookup
+	policy   ReviewPolicy
+	now      func() time.Time
 }
 
-func NewReviewHandler(service *service.ReviewService, producer producer.RatingPublisher) *ReviewHandler {
+func NewReviewHandler(service *service.ReviewService, producer producer.RatingPublisher, users UserLookup, games GameLookup, policy ReviewPolicy) *ReviewHandler {
 	return &ReviewHandler{
 		service:  service,
 		producer: producer,
+		users:    users,
+		games:    games,
+		policy:   policy,
+		now:      time.Now,
 	}
 }
 
 func (h *ReviewHandler) CreateReview(ctx context.Context, req *socialpb.CreateReviewRequest) (*socialpb.CreateReviewResponse, error) {
 	userId, err := utils.GetUserID(ctx)
 	if err != nil {
-		log.Warn().Err(err).Msg("ReviewHandler.CreateReview: failed to extract user_id from context")
+		log.Ctx(ctx).Warn().Err(err).Msg("ReviewHandler.CreateReview: failed to extract user_id from context")
 		return nil, status.Error(codes.PermissionDenied, err.Error())
 	}
 
 	req.UserId = userId
 
-	log.Info().
+	log.Ctx(ctx).Debug().
 		Str("user_id", userId).
 		Str("game_id", req.GameId).
 		Int32("rating", req.Rating).
 		Msg("ReviewHandler.CreateReview: attempt")
 
+	if _, err := uuid.Parse(req.GameId); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "game_id must be a UUID")
+	}
+
+	game, err := h.games.GetGame(ctx, req.GameId)
+	if err != nil {
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("game_id", req.GameId).
+			Msg("ReviewHandler.CreateReview: game check failed (games-service)")
+		return nil, lookupError(ctx, err, "games-service", "game not found")
+	}
+
+	if h.policy.ReleasedOnly && !released(game, h.now()) {
+		log.Ctx(ctx).Debug().
+			Str("game_id", req.GameId).
+			Str("first_release_date", game.GetFirstReleaseDate()).
+			Msg("ReviewHandler.CreateReview: game not released")
+		return nil, status.Error(codes.FailedPrecondition, "game has not been released yet")
+	}
+
 	review, err := h.service.CreateReview(ctx, req)
 	if err != nil {
 		if errors.Is(err, errs.ErrReviewExists) {
-			log.Warn().
+			log.Ctx(ctx).Warn().
 				Str("user_id", userId).
 				Str("game_id", req.GameId).
 				Msg("ReviewHandler.CreateReview: review already exists")
 			return nil, status.Error(codes.AlreadyExists, err.Error())
 		}
 
-		log.Error().
+		log.Ctx(ctx).Error().
 			Err(err).
 			Str("user_id", userId).
 			Str("game_id", req.GameId).
@@ -64,132 +105,136 @@ func (h *ReviewHandler) CreateReview(ctx context.Context, req *socialpb.CreateRe
 		return nil, status.Error(codes.Internal, "internal error during review creation")
 	}
 
-	if err := h.producer.Publish(context.Background(), review.GameID); err != nil {
-		log.Error().
-			Err(err).
-			Str("game_id", review.GameID.String()).
-			Msg("ReviewHandler.CreateReview: failed to publish rating update to broker")
-	} else {
-		log.Debug().Str("game_id", review.GameID.String()).Msg("ReviewHandler.CreateReview: rating update published")
-	}
+	publishRating(ctx, h.service, h.producer, review.GameID, "ReviewHandler.CreateReview")
 
-	log.Info().
+	log.Ctx(ctx).Info().
 		Str("review_id", review.Id.String()).
 		Str("user_id", userId).
 		Msg("ReviewHandler.CreateReview: success")
 
-	return &socialpb.CreateReviewResponse{Review: &socialpb.Review{
-		Id:        review.Id.String(),
-		UserId:    review.UserID.String(),
-		GameId:    review.GameID.String(),
-		Rating:    int32(review.Rating),
-		Text:      review.Text,
-		CreatedAt: timestamppb.New(review.CreatedAt),
-		UpdatedAt: timestamppb.New(review.CreatedAt),
-	}}, nil
+	// socialpb.Review has no version, so the token for later edits travels
+	// as a header.
+	_ = grpc.SetHeader(ctx, metadata.Pairs(ETagHeader, formatETag(review.Version)))
+
+	return &socialpb.CreateReviewResponse{Review: reviewToPB(review)}, nil
+}
+
+// publishRating sends the game's new rating summary to the broker. Failures
+// are logged only: the write they follow has already succeeded. Nothing is
+// sent when the summary cannot be computed, rather than an empty rating.
+func publishRating(ctx context.Context, service *service.ReviewService, producer producer.RatingPublisher, gameID uuid.UUID, caller string) {
+	summary, err := service.GetRatingSummary(ctx, gameID)
+	if err != nil {
+		log.Ctx(ctx).Warn().
+			Err(err).
+			Str("game_id", gameID.String()).
+			Msg(caller + ": failed to compute rating summary")
+		return
+	}
+
+	if err := producer.Publish(context.WithoutCancel(ctx), gameID, summary); err != nil {
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("game_id", gameID.String()).
+			Msg(caller + ": failed to publish rating update to broker")
+	} else {
+		log.Ctx(ctx).Debug().Str("game_id", gameID.String()).Msg(caller + ": rating update published")
+	}
 }
 
 func (h *ReviewHandler) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest) (*socialpb.GetFeedResponse, error) {
-	log.Info().Int32("limit", req.Limit).Msg("ReviewHandler.GetFeed: fetching reviews")
+	log.Ctx(ctx).Debug().Int32("limit", req.Limit).Msg("ReviewHandler.GetFeed: fetching reviews")
 
-	reviews, err := h.service.GetFeed(ctx, req)
+	mask, err := readMask(ctx)
 	if err != nil {
-		log.Error().Err(err).Msg("ReviewHandler.GetFeed: service error")
-		return nil, status.Error(codes.Internal, "failed to get reviews")
+		return nil, err
 	}
 
-	revpb := make([]*socialpb.Review, 0, len(reviews))
-	for _, rev := range reviews {
-		revpb = append(revpb, &socialpb.Review{
-			Id:        rev.Id.String(),
-			UserId:    rev.UserID.String(),
-			GameId:    rev.GameID.String(),
-			Rating:    int32(rev.Rating),
-			Text:      rev.Text,
-			CreatedAt: timestamppb.New(rev.CreatedAt),
-			UpdatedAt: timestamppb.New(rev.UpdatedAt),
-		})
+	reviews, err := h.service.GetFeed(ctx, req)
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Msg("ReviewHandler.GetFeed: service error")
+		return nil, status.Error(codes.Internal, "failed to get reviews")
 	}
 
-	return &socialpb.GetFeedResponse{Reviews: revpb}, nil
+	return &socialpb.GetFeedResponse{Reviews: reviewsToPB(reviews, mask)}, nil
 }
 
 func (h *ReviewHandler) GetUserReviews(ctx context.Context, req *socialpb.GetUserReviewsRequest) (*socialpb.GetUserReviewsResponse, error) {
-	log.Info().Str("target_user_id", req.UserId).Msg("ReviewHandler.GetUserReviews: fetching reviews")
+	log.Ctx(ctx).Debug().Str("target_user_id", req.UserId).Msg("ReviewHandler.GetUserReviews: fetching reviews")
+
+	mask, err := readMask(ctx)
+	if err != nil {
+		return nil, err
+	}
 
-	_, err := microservice.AuthClient.GetUser(ctx, &authpb.GetUserRequest{
-		UserId: req.UserId,
-	})
+	_, err = h.users.GetUser(ctx, req.UserId)
 	if err != nil {
-		log.Warn().
+		log.Ctx(ctx).Warn().
 			Err(err).
 			Str("target_user_id", req.UserId).
 			Msg("ReviewHandler.GetUserReviews: target user check failed (auth-service)")
-		return nil, status.Error(codes.NotFound, "user not found")
+		return nil, lookupError(ctx, err, "auth-service", "user not found")
 	}
 
-	reviews, err := h.service.GetReviewsByUser(ctx, req)
+	viewerId, _ := utils.GetUserID(ctx)
+
+	reviews, err := h.service.GetReviewsByUser(ctx, req, viewerId)
 	if err != nil {
-		log.Error().
+		log.Ctx(ctx).Error().
 			Err(err).
 			Str("target_user_id", req.UserId).
 			Msg("ReviewHandler.GetUserReviews: service error")
 		return nil, status.Error(codes.Internal, "failed to get reviews")
 	}
 
-	revpb := make([]*socialpb.Review, 0, len(reviews))
-	for _, rev := range reviews {
-		revpb = append(revpb, &socialpb.Review{
-			Id:        rev.Id.String(),
-			UserId:    rev.UserID.String(),
-			GameId:    rev.GameID.String(),
-			Rating:    int32(rev.Rating),
-			Text:      rev.Text,
-			CreatedAt: timestamppb.New(rev.CreatedAt),
-			UpdatedAt: timestamppb.New(rev.UpdatedAt),
-		})
-	}
-
-	return &socialpb.GetUserReviewsResponse{Reviews: revpb}, nil
+	return &socialpb.GetUserReviewsResponse{Reviews: reviewsToPB(reviews, mask)}, nil
 }
 
 func (h *ReviewHandler) GetGameReviews(ctx context.Context, req *socialpb.GetGameReviewsRequest) (*socialpb.GetGameReviewsResponse, error) {
-	log.Info().Str("game_id", req.GameId).Msg("ReviewHandler.GetGameReviews: fetching reviews")
+	log.Ctx(ctx).Debug().Str("game_id", req.GameId).Msg("ReviewHandler.GetGameReviews: fetching reviews")
 
-	_, err := microservice.GamesClient.GetGame(ctx, &gamepb.GetGameRequest{
-		IdType: &gamepb.GetGameRequest_GameId{
-			GameId: req.GameId,
-		},
-	})
+	mask, err := readMask(ctx)
 	if err != nil {
-		log.Warn().
+		return nil, err
+	}
+
+	_, err = h.games.GetGame(ctx, req.GameId)
+	if err != nil {
+		log.Ctx(ctx).Warn().
 			Err(err).
 			Str("game_id", req.GameId).
 			Msg("ReviewHandler.GetGameReviews: game check failed (games-service)")
-		return nil, status.Error(codes.NotFound, "game not found")
+		return nil, lookupError(ctx, err, "games-service", "game not found")
 	}
 
 	reviews, err := h.service.GetReviewsByGame(ctx, req)
 	if err != nil {
-		log.Error().
+		log.Ctx(ctx).Error().
 			Err(err).
 			Str("game_id", req.GameId).
 			Msg("ReviewHandler.GetGameReviews: service error")
 		return nil, status.Error(codes.Internal, "failed to get reviews")
 	}
 
-	revpb := make([]*socialpb.Review, 0, len(reviews))
-	for _, rev := range reviews {
-		revpb = append(revpb, &socialpb.Review{
-			Id:        rev.Id.String(),
-			UserId:    rev.UserID.String(),
-			GameId:    rev.GameID.String(),
-			Rating:    int32(rev.Rating),
-			Text:      rev.Text,
-			CreatedAt: timestamppb.New(rev.CreatedAt),
-			UpdatedAt: timestamppb.New(rev.UpdatedAt),
-		})
+	return &socialpb.GetGameReviewsResponse{Reviews: reviewsToPB(reviews, mask)}, nil
+}
+
+// lookupError translates a failed call to a downstream service. Only a
+// NotFound answer means the entity is missing; an outage or timeout is
+// reported as Unavailable so clients retry instead of caching "not found".
+func lookupError(ctx context.Context, err error, downstream, notFound string) error {
+	if ctx.Err() != nil {
+		return status.FromContextError(ctx.Err()).Err()
 	}
 
-	return &socialpb.GetGameReviewsResponse{Reviews: revpb}, nil
+	switch st := status.Convert(err); st.Code() {
+	case codes.NotFound:
+		return status.Error(codes.NotFound, notFound)
+	case codes.InvalidArgument:
+		return status.Error(codes.InvalidArgument, st.Message())
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted:
+		return status.Error(codes.Unavailable, downstream+" is unavailable")
+	default:
+		return status.Error(codes.Internal, downstream+" lookup failed")
+	}
 }
@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	reviewspb "social-service/gen/go/reviews"
+	"social-service/internal/feed"
+	"social-service/internal/model"
+	"social-service/internal/producer"
+	"social-service/internal/service"
+	"social-service/internal/utils"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ETagHeader carries the etag of the review a response describes.
+const ETagHeader = "etag"
+
+type ReviewEditHandler struct {
+	reviewspb.UnimplementedReviewServiceServer
+	service  *service.ReviewService
+	producer producer.RatingPublisher
+	hub      *feed.Hub
+}
+
+func NewReviewEditHandler(service *service.ReviewService, producer producer.RatingPublisher, hub *feed.Hub) *ReviewEditHandler {
+	return &ReviewEditHandler{
+		service:  service,
+		producer: producer,
+		hub:      hub,
+	}
+}
+
+func (h *ReviewEditHandler) GetReview(ctx context.Context, req *reviewspb.GetReviewRequest) (*reviewspb.GetReviewResponse, error) {
+	reviewId, err := uuid.Parse(req.ReviewId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid review_id")
+	}
+
+	// Anonymous callers see what everyone else sees.
+	viewerId := uuid.Nil
+	if userId, err := utils.GetUserID(ctx); err == nil {
+		viewerId, _ = uuid.Parse(userId)
+	}
+
+	review, err := h.service.GetReview(ctx, reviewId, viewerId)
+	if err != nil {
+		if errors.Is(err, model.ErrReviewNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+
+		log.Ctx(ctx).Error().Err(err).Str("review_id", req.ReviewId).Msg("ReviewEditHandler.GetReview: service error")
+		return nil, status.Error(codes.Internal, "failed to get review")
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(ETagHeader, formatETag(review.Version)))
+
+	return &reviewspb.GetReviewResponse{Review: versionedReviewToPB(review)}, nil
+}
+
+func (h *ReviewEditHandler) UpdateReview(ctx context.Context, req *reviewspb.UpdateReviewRequest) (*reviewspb.UpdateReviewResponse, error) {
+	userId, err := utils.GetUserID(ctx)
+	if err != nil {
+		log.Ctx(ctx).Warn().Err(err).Msg("ReviewEditHandler.UpdateReview: failed to extract user_id from context")
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	authorId, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, status.Error(codes.PermissionDenied, "invalid user_id in metadata")
+	}
+
+	reviewId, err := uuid.Parse(req.ReviewId)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid review_id")
+	}
+
+	version, err := expectedVersion(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	review, err := h.service.UpdateReview(ctx, reviewId, authorId, req.Rating, req.Text, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, model.ErrReviewNotFound):
+			return nil, status.Error(codes.NotFound, err.Error())
+		case errors.Is(err, model.ErrNotReviewEditor):
+			return nil, status.Error(codes.PermissionDenied, err.Error())
+		case errors.Is(err, model.ErrReviewRemoved):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		case errors.Is(err, model.ErrReviewVersionConflict):
+			log.Ctx(ctx).Info().
+				Str("review_id", req.ReviewId).
+				Int("expected_version", version).
+				Msg("ReviewEditHandler.UpdateReview: stale write rejected")
+			return nil, status.Error(codes.Aborted, err.Error())
+		}
+
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("review_id", req.ReviewId).
+			Str("user_id", userId).
+			Msg("ReviewEditHandler.UpdateReview: service error")
+		return nil, status.Error(codes.Internal, "failed to update review")
+	}
+
+	publishRating(ctx, h.service, h.producer, review.GameID, "ReviewEditHandler.UpdateReview")
+
+	log.Ctx(ctx).Info().
+		Str("review_id", req.ReviewId).
+		Str("user_id", userId).
+		Int("version", review.Version).
+		Msg("ReviewEditHandler.UpdateReview: success")
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(ETagHeader, formatETag(review.Version)))
+
+	return &reviewspb.UpdateReviewResponse{Review: versionedReviewToPB(review)}, nil
+}
+
+// expectedVersion reads the version an update is based on from either
+// expected_version or etag. Giving both is allowed as long as they agree.
+func expectedVersion(req *reviewspb.UpdateReviewRequest) (int, error) {
+	if req.Etag == "" {
+		if req.ExpectedVersion < 1 {
+			return 0, errors.New("expected_version or etag is required")
+		}
+		return int(req.ExpectedVersion), nil
+	}
+
+	version, err := parseETag(req.Etag)
+	if err != nil {
+		return 0, err
+	}
+
+	if req.ExpectedVersion != 0 && req.ExpectedVersion != int64(version) {
+		return 0, errors.New("expected_version and etag disagree")
+	}
+
+	return version, nil
+}
+
+// formatETag renders a version as a strong HTTP entity tag, e.g. "3" with
+// the quotes.
+func formatETag(version int) string {
+	return strconv.Quote(strconv.Itoa(version))
+}
+
+func parseETag(etag string) (int, error) {
+	unquoted, err := strconv.Unquote(strings.TrimSpace(etag))
+	if err != nil {
+		return 0, fmt.Errorf("malformed etag %q", etag)
+	}
+
+	version, err := strconv.Atoi(unquoted)
+	if err != nil || version < 1 {
+		return 0, fmt.Errorf("malformed etag %q", etag)
+	}
+
+	return version, nil
+}
+
+func versionedReviewToPB(review *model.Review) *reviewspb.Review {
+	return &reviewspb.Review{
+		Id:        review.Id.String(),
+		UserId:    review.UserID.String(),
+		GameId:    review.GameID.String(),
+		Rating:    int32(review.Rating),
+		Text:      review.Text,
+		CreatedAt: timestamppb.New(review.CreatedAt),
+		UpdatedAt: timestamppb.New(review.UpdatedAt),
+		Version:   int64(review.Version),
+		Etag:      formatETag(review.Version),
+	}
+}
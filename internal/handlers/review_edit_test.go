@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"context"
+	reviewspb "social-service/gen/go/reviews"
+	"social-service/internal/feed"
+	"social-service/internal/service"
+	"social-service/internal/storage"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestReviewEditHandler_UpdateReview(t *testing.T) {
+	svc := service.NewReviewService(storage.NewMemoryReviewStore(), nil, nil, 50)
+	mockProd := new(MockProducer)
+	mockProd.On("Publish", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	h := NewReviewEditHandler(svc, mockProd, feed.NewHub(feed.Options{History: 10, Buffer: 10, MaxSubscribers: 10}))
+
+	authorID := uuid.New()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", authorID.String()))
+
+	review, err := svc.CreateReview(ctx, &socialpb.CreateReviewRequest{
+		UserId: authorID.String(),
+		GameId: uuid.NewString(),
+		Rating: 40,
+		Text:   "meh",
+	})
+	require.NoError(t, err)
+
+	got, err := h.GetReview(ctx, &reviewspb.GetReviewRequest{ReviewId: review.Id.String()})
+	require.NoError(t, err)
+	assert.Equal(t, `"1"`, got.Review.Etag)
+
+	t.Run("update by etag", func(t *testing.T) {
+		resp, err := h.UpdateReview(ctx, &reviewspb.UpdateReviewRequest{
+			ReviewId: review.Id.String(),
+			Rating:   80,
+			Text:     "grew on me",
+			Etag:     got.Review.Etag,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), resp.Review.Version)
+		assert.Equal(t, `"2"`, resp.Review.Etag)
+		assert.Equal(t, int32(80), resp.Review.Rating)
+		mockProd.AssertCalled(t, "Publish", mock.Anything, review.GameID, mock.Anything)
+	})
+
+	t.Run("stale etag is aborted", func(t *testing.T) {
+		_, err := h.UpdateReview(ctx, &reviewspb.UpdateReviewRequest{
+			ReviewId: review.Id.String(),
+			Rating:   10,
+			Etag:     got.Review.Etag,
+		})
+		assert.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("update by expected version", func(t *testing.T) {
+		resp, err := h.UpdateReview(ctx, &reviewspb.UpdateReviewRequest{
+			ReviewId:        review.Id.String(),
+			Rating:          90,
+			ExpectedVersion: 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), resp.Review.Version)
+	})
+
+	t.Run("other user", func(t *testing.T) {
+		otherCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", uuid.NewString()))
+		_, err := h.UpdateReview(otherCtx, &reviewspb.UpdateReviewRequest{
+			ReviewId:        review.Id.String(),
+			ExpectedVersion: 3,
+		})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		_, err := h.UpdateReview(ctx, &reviewspb.UpdateReviewRequest{ReviewId: review.Id.String()})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		_, err := h.UpdateReview(context.Background(), &reviewspb.UpdateReviewRequest{ReviewId: review.Id.String()})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("missing review", func(t *testing.T) {
+		_, err := h.GetReview(ctx, &reviewspb.GetReviewRequest{ReviewId: uuid.NewString()})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *reviewspb.UpdateReviewRequest
+		want    int
+		wantErr string
+	}{
+		{name: "version", req: &reviewspb.UpdateReviewRequest{ExpectedVersion: 4}, want: 4},
+		{name: "etag", req: &reviewspb.UpdateReviewRequest{Etag: `"7"`}, want: 7},
+		{name: "both agree", req: &reviewspb.UpdateReviewRequest{Etag: `"7"`, ExpectedVersion: 7}, want: 7},
+		{name: "both disagree", req: &reviewspb.UpdateReviewRequest{Etag: `"7"`, ExpectedVersion: 6}, wantErr: "disagree"},
+		{name: "unquoted etag", req: &reviewspb.UpdateReviewRequest{Etag: "7"}, wantErr: "malformed etag"},
+		{name: "neither", req: &reviewspb.UpdateReviewRequest{}, wantErr: "required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expectedVersion(tt.req)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatETag(t *testing.T) {
+	version, err := parseETag(formatETag(12))
+	require.NoError(t, err)
+	assert.Equal(t, 12, version)
+}
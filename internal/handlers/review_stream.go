@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	reviewspb "social-service/gen/go/reviews"
+	"social-service/internal/feed"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func (h *ReviewEditHandler) StreamReviews(req *reviewspb.StreamReviewsRequest, stream grpc.ServerStreamingServer[reviewspb.ReviewEvent]) error {
+	ctx := stream.Context()
+
+	filter, err := streamFilter(req)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	sub, err := h.hub.Subscribe(filter, req.Cursor)
+	if err != nil {
+		return streamError(err)
+	}
+	defer sub.Close()
+
+	log.Ctx(ctx).Debug().
+		Int("game_ids", len(filter.GameIDs)).
+		Int("user_ids", len(filter.UserIDs)).
+		Bool("resumed", req.Cursor != "").
+		Msg("ReviewEditHandler.StreamReviews: subscribed")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-sub.Done():
+			// Anything still queued is replayed from the cursor on reconnect.
+			err := sub.Err()
+			log.Ctx(ctx).Info().Err(err).Msg("ReviewEditHandler.StreamReviews: subscription ended")
+			return streamError(err)
+		case event := <-sub.Events():
+			err := stream.Send(&reviewspb.ReviewEvent{
+				Review: versionedReviewToPB(event.Review),
+				Cursor: h.hub.Cursor(event),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func streamFilter(req *reviewspb.StreamReviewsRequest) (feed.Filter, error) {
+	gameIds, err := uuidSet("game_ids", req.GameIds)
+	if err != nil {
+		return feed.Filter{}, err
+	}
+
+	userIds, err := uuidSet("user_ids", req.UserIds)
+	if err != nil {
+		return feed.Filter{}, err
+	}
+
+	return feed.Filter{GameIDs: gameIds, UserIDs: userIds}, nil
+}
+
+func uuidSet(field string, values []string) (map[uuid.UUID]struct{}, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	set := make(map[uuid.UUID]struct{}, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s", field)
+		}
+		set[id] = struct{}{}
+	}
+
+	return set, nil
+}
+
+func streamError(err error) error {
+	switch {
+	case errors.Is(err, feed.ErrCursorExpired):
+		return status.Error(codes.OutOfRange, err.Error())
+	case errors.Is(err, feed.ErrInvalidCursor):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, feed.ErrTooManySubscribers), errors.Is(err, feed.ErrSlowSubscriber):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+
+	return status.Error(codes.Internal, "live feed unavailable")
+}
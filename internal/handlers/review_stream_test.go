@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"context"
+	reviewspb "social-service/gen/go/reviews"
+	"social-service/internal/feed"
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type fakeReviewStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *reviewspb.ReviewEvent
+}
+
+func (s *fakeReviewStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *fakeReviewStream) Send(event *reviewspb.ReviewEvent) error {
+	s.sent <- event
+	return nil
+}
+
+func TestReviewEditHandler_StreamReviews(t *testing.T) {
+	hub := feed.NewHub(feed.Options{History: 10, Buffer: 10, MaxSubscribers: 10})
+	h := NewReviewEditHandler(nil, nil, hub)
+
+	game := uuid.New()
+	review := &model.Review{Id: uuid.New(), UserID: uuid.New(), GameID: game, Rating: 90, Version: 1}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeReviewStream{ctx: ctx, sent: make(chan *reviewspb.ReviewEvent, 1)}
+
+	done := make(chan error, 1)
+	go func() {
+		done <- h.StreamReviews(&reviewspb.StreamReviewsRequest{GameIds: []string{game.String()}}, stream)
+	}()
+
+	// Publish until the handler has subscribed and the review comes through.
+	var got *reviewspb.ReviewEvent
+	require.Eventually(t, func() bool {
+		hub.Publish(&model.Review{Id: uuid.New(), GameID: uuid.New()})
+		hub.Publish(review)
+		select {
+		case got = <-stream.sent:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, review.Id.String(), got.Review.Id)
+	assert.Equal(t, `"1"`, got.Review.Etag)
+	assert.NotEmpty(t, got.Cursor)
+
+	cancel()
+	assert.Equal(t, codes.Canceled, status.Code(<-done))
+
+	t.Run("malformed cursor", func(t *testing.T) {
+		err := h.StreamReviews(&reviewspb.StreamReviewsRequest{Cursor: "nonsense"}, stream)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("cursor from before a restart", func(t *testing.T) {
+		restarted := NewReviewEditHandler(nil, nil, feed.NewHub(feed.Options{History: 10, Buffer: 10, MaxSubscribers: 10}))
+		err := restarted.StreamReviews(&reviewspb.StreamReviewsRequest{Cursor: got.Cursor}, stream)
+		assert.Equal(t, codes.OutOfRange, status.Code(err))
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		err := h.StreamReviews(&reviewspb.StreamReviewsRequest{UserIds: []string{"42"}}, stream)
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
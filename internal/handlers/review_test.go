@@ -3,7 +3,7 @@ package handlers
 import (
 	"context"
 	"errors"
-	"social-service/internal/microservice"
+	"social-service/internal/model"
 	"social-service/internal/service"
 	"social-service/internal/storage"
 	"testing"
@@ -18,7 +18,6 @@ import (
 	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
 	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
 	"github.com/viktoralyoshin/utils/pkg/errs"
-	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
@@ -28,35 +27,33 @@ type MockProducer struct {
 	mock.Mock
 }
 
-func (m *MockProducer) Publish(ctx context.Context, gameID uuid.UUID) error {
-	args := m.Called(ctx, gameID)
+func (m *MockProducer) Publish(ctx context.Context, gameID uuid.UUID, summary *model.RatingSummary) error {
+	args := m.Called(ctx, gameID, summary)
 	return args.Error(0)
 }
 
-type MockAuthClient struct {
+type MockUserLookup struct {
 	mock.Mock
-	authpb.AuthServiceClient
 }
 
-func (m *MockAuthClient) GetUser(ctx context.Context, in *authpb.GetUserRequest, opts ...grpc.CallOption) (*authpb.GetUserResponse, error) {
-	args := m.Called(ctx, in)
+func (m *MockUserLookup) GetUser(ctx context.Context, userID string) (*authpb.GetUserResponse, error) {
+	args := m.Called(ctx, userID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
 	return args.Get(0).(*authpb.GetUserResponse), args.Error(1)
 }
 
-type MockGamesClient struct {
+type MockGameLookup struct {
 	mock.Mock
-	gamepb.GameServiceClient
 }
 
-func (m *MockGamesClient) GetGame(ctx context.Context, in *gamepb.GetGameRequest, opts ...grpc.CallOption) (*gamepb.GetGameResponse, error) {
-	args := m.Called(ctx, in)
+func (m *MockGameLookup) GetGame(ctx context.Context, gameID string) (*gamepb.Game, error) {
+	args := m.Called(ctx, gameID)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*gamepb.GetGameResponse), args.Error(1)
+	return args.Get(0).(*gamepb.Game), args.Error(1)
 }
 
 func setupHandlerTest(t *testing.T) (*ReviewHandler, *MockProducer, sqlmock.Sqlmock, func()) {
@@ -64,10 +61,10 @@ func setupHandlerTest(t *testing.T) (*ReviewHandler, *MockProducer, sqlmock.Sqlm
 	require.NoError(t, err)
 
 	repo := storage.NewReviewRepo(db)
-	svc := service.NewReviewService(repo)
+	svc := service.NewReviewService(repo, nil, nil, 50)
 
 	mockProd := new(MockProducer)
-	h := NewReviewHandler(svc, mockProd)
+	h := NewReviewHandler(svc, mockProd, new(MockUserLookup), new(MockGameLookup), ReviewPolicy{})
 
 	return h, mockProd, dbMock, func() {
 		dbMock.ExpectClose()
@@ -84,16 +81,27 @@ func TestReviewHandler_CreateReview(t *testing.T) {
 	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", userID.String()))
 	req := &socialpb.CreateReviewRequest{GameId: gameID.String(), Rating: 5, Text: "Great!"}
 
+	gamesMock := h.games.(*MockGameLookup)
+	gamesMock.On("GetGame", mock.Anything, gameID.String()).Return(&gamepb.Game{Id: gameID.String()}, nil)
+
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}).
-			AddRow(uuid.New().String(), userID.String(), gameID.String(), 5, "Great!", time.Now(), time.Now())
+		createdAt := time.Now().Add(-time.Second).UTC()
+		updatedAt := time.Now().UTC()
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), userID.String(), gameID.String(), 5, "Great!", createdAt, updatedAt, 1)
 
 		dbMock.ExpectQuery(`INSERT INTO`).WillReturnRows(rows)
-		mockProd.On("Publish", mock.Anything, gameID).Return(nil).Once()
+		dbMock.ExpectQuery(`SELECT COUNT`).
+			WithArgs(gameID, false).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "avg", "exists"}).AddRow(1, 5.0, false))
+		mockProd.On("Publish", mock.Anything, gameID, mock.MatchedBy(func(s *model.RatingSummary) bool {
+			return s != nil && s.Count == 1 && s.Average == 5.0
+		})).Return(nil).Once()
 
 		resp, err := h.CreateReview(ctx, req)
 		assert.NoError(t, err)
-		assert.NotNil(t, resp)
+		assert.Equal(t, createdAt, resp.Review.CreatedAt.AsTime())
+		assert.Equal(t, updatedAt, resp.Review.UpdatedAt.AsTime())
 	})
 
 	t.Run("permission denied - no metadata", func(t *testing.T) {
@@ -101,6 +109,27 @@ func TestReviewHandler_CreateReview(t *testing.T) {
 		assert.Equal(t, codes.PermissionDenied, status.Code(err))
 	})
 
+	t.Run("malformed game_id", func(t *testing.T) {
+		_, err := h.CreateReview(ctx, &socialpb.CreateReviewRequest{GameId: "not-a-uuid", Rating: 5})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("game not found", func(t *testing.T) {
+		missing := uuid.NewString()
+		gamesMock.On("GetGame", mock.Anything, missing).Return(nil, status.Error(codes.NotFound, "no such game")).Once()
+
+		_, err := h.CreateReview(ctx, &socialpb.CreateReviewRequest{GameId: missing, Rating: 5})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("games-service unavailable", func(t *testing.T) {
+		flaky := uuid.NewString()
+		gamesMock.On("GetGame", mock.Anything, flaky).Return(nil, status.Error(codes.Unavailable, "down")).Once()
+
+		_, err := h.CreateReview(ctx, &socialpb.CreateReviewRequest{GameId: flaky, Rating: 5})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
 	t.Run("already exists", func(t *testing.T) {
 		dbMock.ExpectQuery(`INSERT INTO`).WillReturnError(errs.ErrReviewExists)
 		_, err := h.CreateReview(ctx, req)
@@ -113,12 +142,27 @@ func TestReviewHandler_CreateReview(t *testing.T) {
 		assert.Equal(t, codes.Internal, status.Code(err))
 	})
 
+	t.Run("summary failure - nothing published", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), userID.String(), gameID.String(), 5, "Great!", time.Now(), time.Now(), 1)
+
+		dbMock.ExpectQuery(`INSERT INTO`).WillReturnRows(rows)
+		dbMock.ExpectQuery(`SELECT COUNT`).WillReturnError(errors.New("summary fail"))
+
+		resp, err := h.CreateReview(ctx, req)
+		assert.NoError(t, err)
+		assert.NotNil(t, resp)
+		mockProd.AssertNotCalled(t, "Publish", mock.Anything, gameID, (*model.RatingSummary)(nil))
+	})
+
 	t.Run("producer failure - still success response", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}).
-			AddRow(uuid.New().String(), userID.String(), gameID.String(), 5, "Great!", time.Now(), time.Now())
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), userID.String(), gameID.String(), 5, "Great!", time.Now(), time.Now(), 1)
 
 		dbMock.ExpectQuery(`INSERT INTO`).WillReturnRows(rows)
-		mockProd.On("Publish", mock.Anything, gameID).Return(errors.New("kafka error")).Once()
+		dbMock.ExpectQuery(`SELECT COUNT`).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "avg", "exists"}).AddRow(1, 5.0, false))
+		mockProd.On("Publish", mock.Anything, gameID, mock.Anything).Return(errors.New("kafka error")).Once()
 
 		resp, err := h.CreateReview(ctx, req)
 		assert.NoError(t, err)
@@ -131,9 +175,9 @@ func TestReviewHandler_GetFeed(t *testing.T) {
 	defer cleanup()
 
 	t.Run("success with rows mapping", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}).
-			AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 5, "T1", time.Now(), time.Now()).
-			AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 4, "T2", time.Now(), time.Now())
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 5, "T1", time.Now(), time.Now(), 1).
+			AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 4, "T2", time.Now(), time.Now(), 1)
 
 		dbMock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 		resp, err := h.GetFeed(context.Background(), &socialpb.GetFeedRequest{Limit: 2})
@@ -146,27 +190,98 @@ func TestReviewHandler_GetFeed(t *testing.T) {
 		_, err := h.GetFeed(context.Background(), &socialpb.GetFeedRequest{Limit: 1})
 		assert.Equal(t, codes.Internal, status.Code(err))
 	})
+
+	t.Run("read mask", func(t *testing.T) {
+		gameID := uuid.New()
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), uuid.New().String(), gameID.String(), 5, "long text", time.Now(), time.Now(), 1)
+
+		dbMock.ExpectQuery(`SELECT`).WillReturnRows(rows)
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ReadMaskHeader, "id, rating,game_id"))
+
+		resp, err := h.GetFeed(ctx, &socialpb.GetFeedRequest{Limit: 1})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.Reviews[0].Id)
+		assert.Equal(t, gameID.String(), resp.Reviews[0].GameId)
+		assert.Equal(t, int32(5), resp.Reviews[0].Rating)
+		assert.Empty(t, resp.Reviews[0].Text)
+		assert.Empty(t, resp.Reviews[0].UserId)
+		assert.Nil(t, resp.Reviews[0].CreatedAt)
+	})
+
+	t.Run("invalid read mask", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(ReadMaskHeader, "id,author"))
+		_, err := h.GetFeed(ctx, &socialpb.GetFeedRequest{Limit: 1})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestReviewHandler_CreateReview_ReleasedOnly(t *testing.T) {
+	h, mockProd, dbMock, cleanup := setupHandlerTest(t)
+	defer cleanup()
+
+	h.policy = ReviewPolicy{ReleasedOnly: true}
+	h.now = func() time.Time { return time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC) }
+
+	userID := uuid.New()
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", userID.String()))
+	gamesMock := h.games.(*MockGameLookup)
+
+	for _, tt := range []struct {
+		name        string
+		releaseDate string
+		wantCode    codes.Code
+	}{
+		{"released", "2020-03-20", codes.OK},
+		{"released, timestamp", "2026-10-18T12:00:00Z", codes.OK},
+		{"released, unix seconds", "1584662400", codes.OK},
+		{"upcoming", "2027-01-01", codes.FailedPrecondition},
+		{"unknown date", "", codes.FailedPrecondition},
+		{"unparseable date", "TBA", codes.FailedPrecondition},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			gameID := uuid.New()
+			gamesMock.On("GetGame", mock.Anything, gameID.String()).
+				Return(&gamepb.Game{Id: gameID.String(), FirstReleaseDate: tt.releaseDate}, nil).Once()
+
+			if tt.wantCode == codes.OK {
+				dbMock.ExpectQuery(`INSERT INTO`).WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+					AddRow(uuid.New().String(), userID.String(), gameID.String(), 80, "", time.Now(), time.Now(), 1))
+				dbMock.ExpectQuery(`SELECT COUNT`).
+					WillReturnRows(sqlmock.NewRows([]string{"count", "avg", "exists"}).AddRow(1, 80.0, false))
+				mockProd.On("Publish", mock.Anything, gameID, mock.Anything).Return(nil).Once()
+			}
+
+			_, err := h.CreateReview(ctx, &socialpb.CreateReviewRequest{GameId: gameID.String(), Rating: 80})
+			assert.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+
+	assert.NoError(t, dbMock.ExpectationsWereMet())
 }
 
 func TestReviewHandler_GetUserReviews(t *testing.T) {
 	h, _, dbMock, cleanup := setupHandlerTest(t)
 	defer cleanup()
 
-	authMock := new(MockAuthClient)
-	oldAuth := microservice.AuthClient
-	microservice.AuthClient = authMock
-	defer func() { microservice.AuthClient = oldAuth }()
+	authMock := h.users.(*MockUserLookup)
 
 	targetUID := uuid.New().String()
 
 	t.Run("user not found in auth-service", func(t *testing.T) {
-		authMock.On("GetUser", mock.Anything, mock.Anything).Return(nil, errors.New("grpc error")).Once()
+		authMock.On("GetUser", mock.Anything, mock.Anything).Return(nil, status.Error(codes.NotFound, "no such user")).Once()
 		_, err := h.GetUserReviews(context.Background(), &socialpb.GetUserReviewsRequest{UserId: targetUID})
 		assert.Equal(t, codes.NotFound, status.Code(err))
 	})
 
+	t.Run("auth-service timeout", func(t *testing.T) {
+		authMock.On("GetUser", mock.Anything, mock.Anything).Return(nil, status.Error(codes.DeadlineExceeded, "deadline exceeded")).Once()
+		_, err := h.GetUserReviews(context.Background(), &socialpb.GetUserReviewsRequest{UserId: targetUID})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
 	t.Run("internal service error", func(t *testing.T) {
-		authMock.On("GetUser", mock.Anything, mock.Anything).Return(&authpb.GetUserResponse{}, nil).Once()
+		authMock.On("GetUser", mock.Anything, targetUID).Return(&authpb.GetUserResponse{UserId: targetUID}, nil).Once()
 		dbMock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db error"))
 
 		_, err := h.GetUserReviews(context.Background(), &socialpb.GetUserReviewsRequest{UserId: targetUID})
@@ -174,36 +289,51 @@ func TestReviewHandler_GetUserReviews(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		authMock.On("GetUser", mock.Anything, mock.Anything).Return(&authpb.GetUserResponse{}, nil).Once()
-		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}).
-			AddRow(uuid.New().String(), targetUID, uuid.New().String(), 5, "T", time.Now(), time.Now())
+		authMock.On("GetUser", mock.Anything, targetUID).Return(&authpb.GetUserResponse{UserId: targetUID}, nil).Once()
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), targetUID, uuid.New().String(), 5, "T", time.Now(), time.Now(), 1)
 		dbMock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 
 		resp, err := h.GetUserReviews(context.Background(), &socialpb.GetUserReviewsRequest{UserId: targetUID})
 		assert.NoError(t, err)
 		assert.Len(t, resp.Reviews, 1)
 	})
+
+	t.Run("own profile includes shadow-banned reviews", func(t *testing.T) {
+		authMock.On("GetUser", mock.Anything, targetUID).Return(&authpb.GetUserResponse{UserId: targetUID}, nil).Once()
+		dbMock.ExpectQuery(`SELECT`).
+			WithArgs(targetUID, 20, 0, true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}))
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", targetUID))
+		_, err := h.GetUserReviews(ctx, &socialpb.GetUserReviewsRequest{UserId: targetUID})
+		assert.NoError(t, err)
+		assert.NoError(t, dbMock.ExpectationsWereMet())
+	})
 }
 
 func TestReviewHandler_GetGameReviews(t *testing.T) {
 	h, _, dbMock, cleanup := setupHandlerTest(t)
 	defer cleanup()
 
-	gamesMock := new(MockGamesClient)
-	oldGames := microservice.GamesClient
-	microservice.GamesClient = gamesMock
-	defer func() { microservice.GamesClient = oldGames }()
+	gamesMock := h.games.(*MockGameLookup)
 
 	gameID := uuid.New().String()
 
 	t.Run("game not found in games-service", func(t *testing.T) {
-		gamesMock.On("GetGame", mock.Anything, mock.Anything).Return(nil, errors.New("grpc error")).Once()
+		gamesMock.On("GetGame", mock.Anything, mock.Anything).Return(nil, status.Error(codes.NotFound, "no such game")).Once()
 		_, err := h.GetGameReviews(context.Background(), &socialpb.GetGameReviewsRequest{GameId: gameID})
 		assert.Equal(t, codes.NotFound, status.Code(err))
 	})
 
+	t.Run("games-service outage", func(t *testing.T) {
+		gamesMock.On("GetGame", mock.Anything, mock.Anything).Return(nil, status.Error(codes.Unavailable, "connection refused")).Once()
+		_, err := h.GetGameReviews(context.Background(), &socialpb.GetGameReviewsRequest{GameId: gameID})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
 	t.Run("internal service error", func(t *testing.T) {
-		gamesMock.On("GetGame", mock.Anything, mock.Anything).Return(&gamepb.GetGameResponse{}, nil).Once()
+		gamesMock.On("GetGame", mock.Anything, gameID).Return(&gamepb.Game{Id: gameID}, nil).Once()
 		dbMock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db error"))
 
 		_, err := h.GetGameReviews(context.Background(), &socialpb.GetGameReviewsRequest{GameId: gameID})
@@ -211,9 +341,9 @@ func TestReviewHandler_GetGameReviews(t *testing.T) {
 	})
 
 	t.Run("success", func(t *testing.T) {
-		gamesMock.On("GetGame", mock.Anything, mock.Anything).Return(&gamepb.GetGameResponse{}, nil).Once()
-		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}).
-			AddRow(uuid.New().String(), uuid.New().String(), gameID, 5, "T", time.Now(), time.Now())
+		gamesMock.On("GetGame", mock.Anything, gameID).Return(&gamepb.Game{Id: gameID}, nil).Once()
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), uuid.New().String(), gameID, 5, "T", time.Now(), time.Now(), 1)
 		dbMock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 
 		resp, err := h.GetGameReviews(context.Background(), &socialpb.GetGameReviewsRequest{GameId: gameID})
@@ -221,3 +351,29 @@ func TestReviewHandler_GetGameReviews(t *testing.T) {
 		assert.NotNil(t, resp)
 	})
 }
+
+func TestLookupError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want codes.Code
+	}{
+		{"not found", context.Background(), status.Error(codes.NotFound, "missing"), codes.NotFound},
+		{"invalid id", context.Background(), status.Error(codes.InvalidArgument, "bad id"), codes.InvalidArgument},
+		{"unavailable", context.Background(), status.Error(codes.Unavailable, "down"), codes.Unavailable},
+		{"circuit open", context.Background(), status.Error(codes.Unavailable, "games-service: circuit breaker is open"), codes.Unavailable},
+		{"unknown error", context.Background(), errors.New("boom"), codes.Internal},
+		{"caller cancelled", cancelled, status.Error(codes.Canceled, "context canceled"), codes.Canceled},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := lookupError(tt.ctx, tt.err, "games-service", "game not found")
+			assert.Equal(t, tt.want, status.Code(err))
+		})
+	}
+}
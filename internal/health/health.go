@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// Check probes a single dependency and returns nil while it is usable.
+type Check func(ctx context.Context) error
+
+// Checker periodically runs dependency checks and publishes the result on a
+// grpc.health.v1 server. A service is SERVING only while every dependency it
+// was registered with passes.
+type Checker struct {
+	server   *grpchealth.Server
+	checks   map[string]Check
+	services map[string][]string
+	interval time.Duration
+	timeout  time.Duration
+
+	mu     sync.Mutex
+	failed map[string]error
+}
+
+func NewChecker(interval, timeout time.Duration) *Checker {
+	return &Checker{
+		server:   grpchealth.NewServer(),
+		checks:   make(map[string]Check),
+		services: make(map[string][]string),
+		interval: interval,
+		timeout:  timeout,
+		failed:   make(map[string]error),
+	}
+}
+
+// Server returns the health service to register on the gRPC server.
+func (c *Checker) Server() healthpb.HealthServer {
+	return c.server
+}
+
+// AddCheck registers a named dependency probe.
+func (c *Checker) AddCheck(name string, check Check) {
+	c.checks[name] = check
+}
+
+// AddService declares which dependencies a service name needs in order to
+// serve traffic. The empty name is the overall server status.
+func (c *Checker) AddService(service string, dependencies ...string) {
+	c.services[service] = dependencies
+	c.server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+}
+
+// Run checks dependencies immediately and then every interval until ctx is
+// cancelled.
+func (c *Checker) Run(ctx context.Context) {
+	c.CheckOnce(ctx)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.CheckOnce(ctx)
+		}
+	}
+}
+
+// CheckOnce runs all probes concurrently and updates every service status.
+func (c *Checker) CheckOnce(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	results := make(map[string]error, len(c.checks))
+
+	var (
+		wg sync.WaitGroup
+		mu sync.Mutex
+	)
+
+	for name, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := check(ctx)
+
+			mu.Lock()
+			results[name] = err
+			mu.Unlock()
+		}()
+	}
+
+	wg.Wait()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for name, err := range results {
+		prev, wasFailing := c.failed[name]
+
+		switch {
+		case err != nil && !wasFailing:
+			log.Warn().Err(err).Str("dependency", name).Msg("health: dependency became unhealthy")
+		case err == nil && wasFailing:
+			log.Info().AnErr("previous_error", prev).Str("dependency", name).Msg("health: dependency recovered")
+		}
+
+		if err != nil {
+			c.failed[name] = err
+		} else {
+			delete(c.failed, name)
+		}
+	}
+
+	for service, dependencies := range c.services {
+		status := healthpb.HealthCheckResponse_SERVING
+		for _, dependency := range dependencies {
+			if _, failing := c.failed[dependency]; failing {
+				status = healthpb.HealthCheckResponse_NOT_SERVING
+				break
+			}
+		}
+
+		c.server.SetServingStatus(service, status)
+	}
+}
+
+// Shutdown reports NOT_SERVING for every service and ignores later checks, so
+// load balancers drain the instance before the server stops.
+func (c *Checker) Shutdown() {
+	c.server.Shutdown()
+}
+
+func DBCheck(db *sql.DB) Check {
+	return db.PingContext
+}
+
+// KafkaCheck dials the broker the producers write to.
+func KafkaCheck(broker string) Check {
+	return func(ctx context.Context) error {
+		conn, err := kafka.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			return err
+		}
+
+		return conn.Close()
+	}
+}
+
+// ConnCheck fails while the client connection cannot reach its target. Idle
+// connections are asked to connect so that the next probe sees a real state.
+func ConnCheck(conn *grpc.ClientConn) Check {
+	return func(ctx context.Context) error {
+		switch state := conn.GetState(); state {
+		case connectivity.Idle:
+			conn.Connect()
+			return nil
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			return errors.New("connection is " + state.String())
+		default:
+			return nil
+		}
+	}
+}
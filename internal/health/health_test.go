@@ -0,0 +1,99 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func status(t *testing.T, c *Checker, service string) healthpb.HealthCheckResponse_ServingStatus {
+	resp, err := c.Server().Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+	require.NoError(t, err)
+
+	return resp.Status
+}
+
+func TestChecker_CheckOnce(t *testing.T) {
+	var dbErr error
+
+	c := NewChecker(time.Second, time.Second)
+	c.AddCheck("postgres", func(ctx context.Context) error { return dbErr })
+	c.AddCheck("kafka", func(ctx context.Context) error { return errors.New("broker down") })
+	c.AddService("", "postgres")
+	c.AddService("social.SocialService", "postgres", "kafka")
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, ""), "not serving before the first check")
+
+	c.CheckOnce(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, c, ""))
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, "social.SocialService"))
+
+	dbErr = errors.New("connection refused")
+	c.CheckOnce(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, ""))
+
+	dbErr = nil
+	c.CheckOnce(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, status(t, c, ""))
+}
+
+func TestChecker_Timeout(t *testing.T) {
+	c := NewChecker(time.Second, 10*time.Millisecond)
+	c.AddCheck("postgres", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	c.AddService("", "postgres")
+
+	c.CheckOnce(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, ""))
+}
+
+func TestChecker_Shutdown(t *testing.T) {
+	c := NewChecker(time.Second, time.Second)
+	c.AddCheck("postgres", func(ctx context.Context) error { return nil })
+	c.AddService("", "postgres")
+
+	c.CheckOnce(context.Background())
+	c.Shutdown()
+	c.CheckOnce(context.Background())
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status(t, c, ""))
+}
+
+func TestDBCheck(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.MonitorPingsOption(true))
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	mock.ExpectPing()
+	assert.NoError(t, DBCheck(db)(context.Background()))
+
+	mock.ExpectPing().WillReturnError(errors.New("connection refused"))
+	assert.Error(t, DBCheck(db)(context.Background()))
+}
+
+func TestKafkaCheck(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	assert.Error(t, KafkaCheck("127.0.0.1:1")(ctx))
+}
+
+func TestConnCheck(t *testing.T) {
+	conn, err := grpc.NewClient("127.0.0.1:1", grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	assert.NoError(t, ConnCheck(conn)(context.Background()), "idle connections are not failures")
+
+	require.NoError(t, conn.Close())
+	assert.Error(t, ConnCheck(conn)(context.Background()))
+}
@@ -0,0 +1,187 @@
+// Package idempotency replays the response of a write retried with the same
+// idempotency-key header instead of executing it twice.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"path"
+	"social-service/internal/utils"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	KeyHeader = "idempotency-key"
+	// ReplayedHeader is set on responses served from a stored record.
+	ReplayedHeader = "idempotency-replayed"
+
+	maxKeyLength = 255
+)
+
+type Options struct {
+	// TTL is how long a key and its response are kept.
+	TTL time.Duration
+	// Lease is how long a key stays claimed by a request that has not
+	// finished, e.g. because the instance running it died.
+	Lease time.Duration
+}
+
+// UnaryServerInterceptor applies to the RPCs named in methods, e.g.
+// "CreateReview", when the caller sends an idempotency-key header. Keys are
+// scoped to the method and the calling user. A retry with the same request
+// gets the stored response; reusing a key for a different request fails with
+// InvalidArgument, and a retry racing the original fails with Aborted. Failed
+// calls are not stored, so they can be retried.
+func UnaryServerInterceptor(store Store, methods []string, opts Options) grpc.UnaryServerInterceptor {
+	enabled := make(map[string]bool, len(methods))
+	for _, method := range methods {
+		enabled[method] = true
+	}
+
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := path.Base(info.FullMethod)
+		if !enabled[method] {
+			return handler(ctx, req)
+		}
+
+		key := incomingKey(ctx)
+		if key == "" {
+			return handler(ctx, req)
+		}
+		if len(key) > maxKeyLength {
+			return nil, status.Errorf(codes.InvalidArgument, "%s must be at most %d characters", KeyHeader, maxKeyLength)
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+
+		hash, err := requestHash(msg)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to hash request")
+		}
+
+		userId, _ := utils.GetUserID(ctx)
+		scoped := method + "|" + userId + "|" + key
+
+		existing, err := store.Reserve(ctx, scoped, hash, opts.TTL, opts.Lease)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("method", method).Msg("idempotency: store failed, executing without a key")
+			return handler(ctx, req)
+		}
+
+		if existing != nil {
+			return replay(ctx, existing, hash)
+		}
+
+		// Headers the handler sets, e.g. an etag, are part of the response
+		// a retry must get back.
+		var recorder *headerRecorder
+		if stream := grpc.ServerTransportStreamFromContext(ctx); stream != nil {
+			recorder = &headerRecorder{ServerTransportStream: stream}
+			ctx = grpc.NewContextWithServerTransportStream(ctx, recorder)
+		}
+
+		resp, err := handler(ctx, req)
+
+		// The outcome must be recorded even if the caller has gone away,
+		// since that is exactly when it retries.
+		storeCtx := context.WithoutCancel(ctx)
+
+		if err != nil {
+			if relErr := store.Release(storeCtx, scoped); relErr != nil {
+				log.Ctx(ctx).Error().Err(relErr).Str("method", method).Msg("idempotency: failed to release key")
+			}
+			return nil, err
+		}
+
+		if respMsg, ok := resp.(proto.Message); ok {
+			var header metadata.MD
+			if recorder != nil {
+				header = recorder.header
+			}
+
+			stored, marshalErr := anypb.New(respMsg)
+			if marshalErr == nil {
+				marshalErr = store.Complete(storeCtx, scoped, stored, header)
+			}
+			if marshalErr != nil {
+				log.Ctx(ctx).Error().Err(marshalErr).Str("method", method).Msg("idempotency: failed to store response")
+			}
+		}
+
+		return resp, nil
+	}
+}
+
+func replay(ctx context.Context, existing *Record, hash []byte) (any, error) {
+	if !bytes.Equal(existing.RequestHash, hash) {
+		return nil, status.Errorf(codes.InvalidArgument, "%s was already used for a different request", KeyHeader)
+	}
+
+	if existing.Response == nil {
+		return nil, status.Error(codes.Aborted, "a request with this idempotency key is still in progress")
+	}
+
+	resp, err := existing.Response.UnmarshalNew()
+	if err != nil {
+		log.Ctx(ctx).Error().Err(err).Str("type", existing.Response.TypeUrl).Msg("idempotency: failed to decode stored response")
+		return nil, status.Error(codes.Internal, "failed to replay response")
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Join(existing.Header, metadata.Pairs(ReplayedHeader, "true")))
+	log.Ctx(ctx).Debug().Msg("idempotency: replayed stored response")
+
+	return resp, nil
+}
+
+// headerRecorder passes response headers through while keeping a copy.
+type headerRecorder struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (r *headerRecorder) SetHeader(md metadata.MD) error {
+	r.header = metadata.Join(r.header, md)
+	return r.ServerTransportStream.SetHeader(md)
+}
+
+func (r *headerRecorder) SendHeader(md metadata.MD) error {
+	r.header = metadata.Join(r.header, md)
+	return r.ServerTransportStream.SendHeader(md)
+}
+
+// requestHash identifies a request by its deterministic wire encoding.
+func requestHash(msg proto.Message) ([]byte, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+func incomingKey(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+
+	values := md.Get(KeyHeader)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
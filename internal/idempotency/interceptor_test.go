@@ -0,0 +1,188 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type fakeTransportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+// countingHandler answers with the number of times it ran.
+func countingHandler(calls *int) grpc.UnaryHandler {
+	return func(ctx context.Context, req any) (any, error) {
+		*calls++
+		return wrapperspb.Int64(int64(*calls)), nil
+	}
+}
+
+func keyContext(user, key string) (context.Context, *fakeTransportStream) {
+	stream := &fakeTransportStream{}
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", user, KeyHeader, key))
+	return grpc.NewContextWithServerTransportStream(ctx, stream), stream
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	info := &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/CreateReview"}
+	opts := Options{TTL: time.Hour, Lease: time.Minute}
+
+	t.Run("retry replays the stored response", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview"}, opts)
+		var calls int
+
+		ctx, _ := keyContext("u1", "k1")
+		first, err := interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		require.NoError(t, err)
+
+		ctx, stream := keyContext("u1", "k1")
+		second, err := interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		require.NoError(t, err)
+
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, first.(*wrapperspb.Int64Value).Value, second.(*wrapperspb.Int64Value).Value)
+		assert.Equal(t, []string{"true"}, stream.header.Get(ReplayedHeader))
+	})
+
+	t.Run("replay restores the handler's headers", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview"}, opts)
+		handler := func(ctx context.Context, req any) (any, error) {
+			_ = grpc.SetHeader(ctx, metadata.Pairs("etag", `"2"`))
+			return wrapperspb.Int64(2), nil
+		}
+
+		ctx, stream := keyContext("u1", "k1")
+		_, err := interceptor(ctx, wrapperspb.String("update"), info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, []string{`"2"`}, stream.header.Get("etag"))
+
+		ctx, stream = keyContext("u1", "k1")
+		_, err = interceptor(ctx, wrapperspb.String("update"), info, handler)
+		require.NoError(t, err)
+		assert.Equal(t, []string{`"2"`}, stream.header.Get("etag"))
+		assert.Equal(t, []string{"true"}, stream.header.Get(ReplayedHeader))
+	})
+
+	t.Run("different payload is rejected", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview"}, opts)
+		var calls int
+
+		ctx, _ := keyContext("u1", "k1")
+		_, err := interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		require.NoError(t, err)
+
+		_, err = interceptor(ctx, wrapperspb.String("other review"), info, countingHandler(&calls))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("keys are scoped to the caller and method", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview", "FileAppeal"}, opts)
+		var calls int
+
+		ctx, _ := keyContext("u1", "k1")
+		_, err := interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		require.NoError(t, err)
+
+		ctx, _ = keyContext("u2", "k1")
+		_, err = interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		require.NoError(t, err)
+
+		_, err = interceptor(ctx, wrapperspb.String("review"), &grpc.UnaryServerInfo{FullMethod: "/moderation.ModerationService/FileAppeal"}, countingHandler(&calls))
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, calls)
+	})
+
+	t.Run("failed call can be retried", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview"}, opts)
+		var calls int
+
+		ctx, _ := keyContext("u1", "k1")
+		_, err := interceptor(ctx, wrapperspb.String("review"), info, func(ctx context.Context, req any) (any, error) {
+			return nil, status.Error(codes.Unavailable, "down")
+		})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+
+		_, err = interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("retry during the first call is aborted", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview"}, opts)
+		ctx, _ := keyContext("u1", "k1")
+
+		var retryErr error
+		_, err := interceptor(ctx, wrapperspb.String("review"), info, func(ctx context.Context, req any) (any, error) {
+			var calls int
+			_, retryErr = interceptor(ctx, req, info, countingHandler(&calls))
+			return wrapperspb.Int64(1), nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, codes.Aborted, status.Code(retryErr))
+	})
+
+	t.Run("without a key or for other methods the handler always runs", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview"}, opts)
+		var calls int
+
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "u1"))
+		_, _ = interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		_, _ = interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+
+		ctx, _ = keyContext("u1", "k1")
+		feed := &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/GetFeed"}
+		_, _ = interceptor(ctx, wrapperspb.String("feed"), feed, countingHandler(&calls))
+		_, _ = interceptor(ctx, wrapperspb.String("feed"), feed, countingHandler(&calls))
+
+		assert.Equal(t, 4, calls)
+	})
+
+	t.Run("overlong key is rejected", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), []string{"CreateReview"}, opts)
+		var calls int
+
+		ctx, _ := keyContext("u1", string(make([]byte, maxKeyLength+1)))
+		_, err := interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Zero(t, calls)
+	})
+
+	t.Run("store failure falls back to executing", func(t *testing.T) {
+		interceptor := UnaryServerInterceptor(failingStore{}, []string{"CreateReview"}, opts)
+		var calls int
+
+		ctx, _ := keyContext("u1", "k1")
+		_, err := interceptor(ctx, wrapperspb.String("review"), info, countingHandler(&calls))
+		require.NoError(t, err)
+		assert.Equal(t, 1, calls)
+	})
+}
+
+type failingStore struct{}
+
+func (failingStore) Reserve(context.Context, string, []byte, time.Duration, time.Duration) (*Record, error) {
+	return nil, errors.New("connection refused")
+}
+
+func (failingStore) Complete(context.Context, string, *anypb.Any, metadata.MD) error { return nil }
+
+func (failingStore) Release(context.Context, string) error { return nil }
@@ -0,0 +1,114 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Record is the state kept for a key: the hash of the request that claimed
+// it and, once that request succeeded, its response and the response
+// headers the handler set.
+type Record struct {
+	RequestHash []byte
+	// Response is nil while the first request is still running.
+	Response *anypb.Any
+	Header   metadata.MD
+}
+
+// Store keeps idempotency records. The in-memory implementation is local to
+// a single instance; storage.IdempotencyRepo shares records across replicas.
+type Store interface {
+	// Reserve claims key for a new request and returns nil, or returns the
+	// live record already holding it. Records expire after ttl; a record
+	// still without a response after lease is considered abandoned and may
+	// be claimed again.
+	Reserve(ctx context.Context, key string, hash []byte, ttl, lease time.Duration) (*Record, error)
+	// Complete stores the response of the request holding key.
+	Complete(ctx context.Context, key string, response *anypb.Any, header metadata.MD) error
+	// Release drops key so the request can be retried.
+	Release(ctx context.Context, key string) error
+}
+
+type memoryRecord struct {
+	Record
+	created time.Time
+	expires time.Time
+}
+
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]*memoryRecord
+	calls   int
+	now     func() time.Time
+}
+
+const sweepEvery = 1024
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		records: make(map[string]*memoryRecord),
+		now:     time.Now,
+	}
+}
+
+func (s *MemoryStore) Reserve(_ context.Context, key string, hash []byte, ttl, lease time.Duration) (*Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+
+	s.calls++
+	if s.calls%sweepEvery == 0 {
+		s.sweep(now)
+	}
+
+	if r, ok := s.records[key]; ok && now.Before(r.expires) {
+		abandoned := r.Response == nil && !now.Before(r.created.Add(lease))
+		if !abandoned {
+			existing := r.Record
+			return &existing, nil
+		}
+	}
+
+	s.records[key] = &memoryRecord{
+		Record:  Record{RequestHash: hash},
+		created: now,
+		expires: now.Add(ttl),
+	}
+
+	return nil, nil
+}
+
+func (s *MemoryStore) Complete(_ context.Context, key string, response *anypb.Any, header metadata.MD) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r, ok := s.records[key]; ok {
+		r.Response = response
+		r.Header = header.Copy()
+	}
+
+	return nil
+}
+
+func (s *MemoryStore) Release(_ context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+
+	return nil
+}
+
+// sweep drops expired records so the map does not grow without bound.
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, r := range s.records {
+		if !now.Before(r.expires) {
+			delete(s.records, key)
+		}
+	}
+}
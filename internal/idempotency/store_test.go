@@ -0,0 +1,54 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestMemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	existing, err := store.Reserve(ctx, "k", []byte("h1"), time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "first reservation claims the key")
+
+	existing, err = store.Reserve(ctx, "k", []byte("h2"), time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, []byte("h1"), existing.RequestHash)
+	assert.Nil(t, existing.Response, "still in progress")
+
+	now = now.Add(2 * time.Minute)
+	existing, err = store.Reserve(ctx, "k", []byte("h3"), time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "an abandoned reservation is taken over")
+
+	response := &anypb.Any{TypeUrl: "type", Value: []byte("v")}
+	require.NoError(t, store.Complete(ctx, "k", response, metadata.Pairs("etag", `"1"`)))
+
+	now = now.Add(30 * time.Minute)
+	existing, err = store.Reserve(ctx, "k", []byte("h3"), time.Hour, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, existing)
+	assert.Equal(t, response, existing.Response, "completed records outlive the lease")
+	assert.Equal(t, []string{`"1"`}, existing.Header.Get("etag"))
+
+	now = now.Add(time.Hour)
+	existing, err = store.Reserve(ctx, "k", []byte("h4"), time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing, "expired records are replaced")
+
+	require.NoError(t, store.Release(ctx, "k"))
+	existing, err = store.Reserve(ctx, "k", []byte("h5"), time.Hour, time.Minute)
+	require.NoError(t, err)
+	assert.Nil(t, existing)
+}
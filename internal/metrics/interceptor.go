@@ -0,0 +1,37 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor records the count, status code and latency of every
+// unary RPC. It should be first in the chain so rejected calls are counted.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		service, method := splitMethod(info.FullMethod)
+		rpcHandled.WithLabelValues(service, method, status.Code(err).String()).Inc()
+		rpcDuration.WithLabelValues(service, method).Observe(time.Since(start).Seconds())
+
+		return resp, err
+	}
+}
+
+// splitMethod turns "/social.SocialService/CreateReview" into its service
+// and method parts.
+func splitMethod(fullMethod string) (string, string) {
+	service, method, ok := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+	if !ok {
+		return "unknown", "unknown"
+	}
+
+	return service, method
+}
@@ -0,0 +1,161 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "social"
+
+// Registry holds every collector exported by the service. A dedicated
+// registry keeps tests independent of the global default one.
+var Registry = prometheus.NewRegistry()
+
+var (
+	rpcHandled = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handled_total",
+		Help:      "RPCs completed on the server, by method and status code.",
+	}, []string{"grpc_service", "grpc_method", "grpc_code"})
+
+	rpcDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Latency of RPCs handled by the server.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"grpc_service", "grpc_method"})
+
+	kafkaPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "kafka_published_total",
+		Help:      "Kafka publish attempts, by topic and result.",
+	}, []string{"topic", "result"})
+
+	downstreamRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "downstream_retries_total",
+		Help:      "Retried calls to downstream services.",
+	}, []string{"downstream"})
+
+	downstreamCircuitOpen = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "downstream_circuit_open",
+		Help:      "1 while the circuit breaker of a downstream service rejects calls.",
+	}, []string{"downstream"})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_requests_total",
+		Help:      "Lookup cache requests, by cache and result (hit, negative_hit or miss).",
+	}, []string{"cache", "result"})
+
+	feedSubscribers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "feed_subscribers",
+		Help:      "Open live feed streams.",
+	})
+
+	feedDisconnects = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "feed_slow_disconnects_total",
+		Help:      "Live feed subscribers disconnected for falling behind.",
+	})
+
+	ReviewsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_created_total",
+		Help:      "Reviews created.",
+	})
+
+	ReviewsUpdated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reviews_updated_total",
+		Help:      "Reviews edited by their author.",
+	})
+
+	ModerationActions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "moderation_actions_total",
+		Help:      "Moderation actions applied, by action.",
+	}, []string{"action"})
+
+	AppealsFiled = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "appeals_filed_total",
+		Help:      "Appeals filed against removed reviews.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		rpcHandled,
+		rpcDuration,
+		kafkaPublished,
+		downstreamRetries,
+		downstreamCircuitOpen,
+		cacheRequests,
+		feedSubscribers,
+		feedDisconnects,
+		ReviewsCreated,
+		ReviewsUpdated,
+		ModerationActions,
+		AppealsFiled,
+	)
+}
+
+// RegisterDB exports the connection pool stats of db.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObservePublish counts a Kafka publish attempt and its outcome.
+func ObservePublish(topic string, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+
+	kafkaPublished.WithLabelValues(topic, result).Inc()
+}
+
+// ObserveRetry counts a retried call to a downstream service.
+func ObserveRetry(downstream string) {
+	downstreamRetries.WithLabelValues(downstream).Inc()
+}
+
+// SetCircuitOpen records the circuit breaker state of a downstream service.
+func SetCircuitOpen(downstream string, open bool) {
+	value := 0.0
+	if open {
+		value = 1
+	}
+
+	downstreamCircuitOpen.WithLabelValues(downstream).Set(value)
+}
+
+// ObserveCache counts a lookup cache request and whether it was served from
+// the cache.
+func ObserveCache(cache, result string) {
+	cacheRequests.WithLabelValues(cache, result).Inc()
+}
+
+// SetFeedSubscribers records the number of open live feed streams.
+func SetFeedSubscribers(n int) {
+	feedSubscribers.Set(float64(n))
+}
+
+// ObserveFeedDisconnect counts a live feed subscriber dropped for being too
+// slow.
+func ObserveFeedDisconnect() {
+	feedDisconnects.Inc()
+}
+
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
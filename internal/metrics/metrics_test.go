@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/CreateReview"}
+
+	ok := func(ctx context.Context, req any) (any, error) { return "ok", nil }
+	denied := func(ctx context.Context, req any) (any, error) {
+		return nil, status.Error(codes.PermissionDenied, "no")
+	}
+
+	resp, err := interceptor(context.Background(), nil, info, ok)
+	assert.NoError(t, err)
+	assert.Equal(t, "ok", resp)
+
+	_, err = interceptor(context.Background(), nil, info, denied)
+	assert.Equal(t, codes.PermissionDenied, status.Code(err))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(rpcHandled.WithLabelValues("social.SocialService", "CreateReview", "OK")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(rpcHandled.WithLabelValues("social.SocialService", "CreateReview", "PermissionDenied")))
+	assert.Equal(t, 1, testutil.CollectAndCount(rpcDuration))
+}
+
+func TestSplitMethod(t *testing.T) {
+	service, method := splitMethod("/moderation.ModerationService/HideReview")
+	assert.Equal(t, "moderation.ModerationService", service)
+	assert.Equal(t, "HideReview", method)
+
+	service, method = splitMethod("garbage")
+	assert.Equal(t, "unknown", service)
+	assert.Equal(t, "unknown", method)
+}
+
+func TestObservePublish(t *testing.T) {
+	ObservePublish("review_events", nil)
+	ObservePublish("review_events", errors.New("broker down"))
+	ObservePublish("review_events", errors.New("broker down"))
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(kafkaPublished.WithLabelValues("review_events", "success")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(kafkaPublished.WithLabelValues("review_events", "failure")))
+}
+
+func TestObserveCache(t *testing.T) {
+	ObserveCache("games", "hit")
+	ObserveCache("games", "hit")
+	ObserveCache("games", "miss")
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(cacheRequests.WithLabelValues("games", "hit")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(cacheRequests.WithLabelValues("games", "miss")))
+}
+
+func TestHandler(t *testing.T) {
+	db, _, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	require.NoError(t, RegisterDB(db, "social"))
+	ReviewsCreated.Inc()
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "social_reviews_created_total 1")
+	assert.Contains(t, rec.Body.String(), `go_sql_open_connections{db_name="social"}`)
+}
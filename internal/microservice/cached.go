@@ -0,0 +1,71 @@
+package microservice
+
+import (
+	"context"
+	"social-service/internal/cache"
+
+	authpb "github.com/viktoralyoshin/playhub-proto/gen/go/auth"
+	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type userGetter interface {
+	GetUser(ctx context.Context, userID string) (*authpb.GetUserResponse, error)
+}
+
+type gameGetter interface {
+	GetGame(ctx context.Context, gameID string) (*gamepb.Game, error)
+}
+
+// IsNotFound reports whether a downstream answered that the entity does not
+// exist, the only error worth caching.
+func IsNotFound(err error) bool {
+	return status.Code(err) == codes.NotFound
+}
+
+// CachedUserClient serves user lookups from a cache.
+type CachedUserClient struct {
+	next  userGetter
+	cache *cache.Cache[*authpb.GetUserResponse]
+}
+
+func NewCachedUserClient(next userGetter, opts cache.Options) *CachedUserClient {
+	opts.Negative = IsNotFound
+
+	return &CachedUserClient{next: next, cache: cache.New[*authpb.GetUserResponse]("users", opts)}
+}
+
+func (c *CachedUserClient) GetUser(ctx context.Context, userID string) (*authpb.GetUserResponse, error) {
+	return c.cache.Get(ctx, userID, func(ctx context.Context) (*authpb.GetUserResponse, error) {
+		return c.next.GetUser(ctx, userID)
+	})
+}
+
+// Invalidate forgets userID, e.g. after the user was deleted.
+func (c *CachedUserClient) Invalidate(userID string) {
+	c.cache.Invalidate(userID)
+}
+
+// CachedGameClient serves game lookups from a cache.
+type CachedGameClient struct {
+	next  gameGetter
+	cache *cache.Cache[*gamepb.Game]
+}
+
+func NewCachedGameClient(next gameGetter, opts cache.Options) *CachedGameClient {
+	opts.Negative = IsNotFound
+
+	return &CachedGameClient{next: next, cache: cache.New[*gamepb.Game]("games", opts)}
+}
+
+func (c *CachedGameClient) GetGame(ctx context.Context, gameID string) (*gamepb.Game, error) {
+	return c.cache.Get(ctx, gameID, func(ctx context.Context) (*gamepb.Game, error) {
+		return c.next.GetGame(ctx, gameID)
+	})
+}
+
+// Invalidate forgets gameID, e.g. after the game was updated or removed.
+func (c *CachedGameClient) Invalidate(gameID string) {
+	c.cache.Invalidate(gameID)
+}
@@ -1,34 +1,125 @@
 package microservice
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"social-service/internal/certs"
 	"social-service/internal/config"
+	"social-service/internal/middleware"
+	"time"
 
-	"github.com/rs/zerolog/log"
 	authpb "github.com/viktoralyoshin/playhub-proto/gen/go/auth"
 	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/credentials"
 )
 
-var GamesClient gamepb.GameServiceClient
-var AuthClient authpb.AuthServiceClient
+// Connections holds the downstream client connections so their state can be
+// probed and they can be closed on shutdown.
+type Connections struct {
+	Games *grpc.ClientConn
+	Auth  *grpc.ClientConn
 
-func Connect(cfg *config.Config) {
-	gamesServiceConn := connect(cfg.GameServiceAddr)
-	authServiceConn := connect(cfg.AuthServiceAddr)
+	certs []*certs.Reloader
+}
+
+func Connect(cfg *config.Config) (*Connections, error) {
+	gamesCreds, gamesCerts, err := certs.ClientCredentials(cfg.GameServiceTLS)
+	if err != nil {
+		return nil, fmt.Errorf("games service tls: %w", err)
+	}
+
+	authCreds, authCerts, err := certs.ClientCredentials(cfg.AuthServiceTLS)
+	if err != nil {
+		return nil, fmt.Errorf("auth service tls: %w", err)
+	}
+
+	policy := RetryPolicy{
+		Timeout:     cfg.DownstreamTimeout,
+		MaxAttempts: cfg.DownstreamMaxAttempts,
+		Backoff:     cfg.DownstreamBackoff,
+		MaxBackoff:  cfg.DownstreamMaxBackoff,
+	}
+
+	gamesServiceConn, err := connect(cfg.GameServiceAddr, gamesCreds, ResilienceInterceptor("games-service", policy,
+		NewBreaker("games-service", cfg.BreakerFailures, cfg.BreakerCooldown)))
+	if err != nil {
+		return nil, err
+	}
+
+	authServiceConn, err := connect(cfg.AuthServiceAddr, authCreds, ResilienceInterceptor("auth-service", policy,
+		NewBreaker("auth-service", cfg.BreakerFailures, cfg.BreakerCooldown)))
+	if err != nil {
+		return nil, errors.Join(err, gamesServiceConn.Close())
+	}
+
+	return &Connections{
+		Games: gamesServiceConn,
+		Auth:  authServiceConn,
+		certs: []*certs.Reloader{gamesCerts, authCerts},
+	}, nil
+}
+
+// WatchCertificates reloads rotated client certificates every interval until
+// ctx is cancelled. New connections to a downstream use the reloaded files.
+func (c *Connections) WatchCertificates(ctx context.Context, interval time.Duration) {
+	for _, reloader := range c.certs {
+		go reloader.Run(ctx, interval)
+	}
+}
+
+func (c *Connections) Close() error {
+	return errors.Join(c.Games.Close(), c.Auth.Close())
+}
+
+func connect(addr string, creds credentials.TransportCredentials, resilience grpc.UnaryClientInterceptor) (*grpc.ClientConn, error) {
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+		grpc.WithChainUnaryInterceptor(middleware.RequestIDClientInterceptor(), resilience),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize grpc connection to %s: %w", addr, err)
+	}
+
+	return conn, nil
+}
+
+// UserClient looks up users in auth-service.
+type UserClient struct {
+	client authpb.AuthServiceClient
+}
+
+func NewUserClient(client authpb.AuthServiceClient) *UserClient {
+	return &UserClient{client: client}
+}
+
+func (c *UserClient) GetUser(ctx context.Context, userID string) (*authpb.GetUserResponse, error) {
+	return c.client.GetUser(ctx, &authpb.GetUserRequest{
+		UserId: userID,
+	})
+}
+
+// GameClient looks up games in games-service.
+type GameClient struct {
+	client gamepb.GameServiceClient
+}
 
-	AuthClient = authpb.NewAuthServiceClient(authServiceConn)
-	GamesClient = gamepb.NewGameServiceClient(gamesServiceConn)
+func NewGameClient(client gamepb.GameServiceClient) *GameClient {
+	return &GameClient{client: client}
 }
 
-func connect(addr string) *grpc.ClientConn {
-	conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+func (c *GameClient) GetGame(ctx context.Context, gameID string) (*gamepb.Game, error) {
+	resp, err := c.client.GetGame(ctx, &gamepb.GetGameRequest{
+		IdType: &gamepb.GetGameRequest_GameId{
+			GameId: gameID,
+		},
+	})
 	if err != nil {
-		log.Fatal().
-			Err(err).
-			Str("target_addr", addr).
-			Msg("failed to initialize grpc connection")
+		return nil, err
 	}
 
-	return conn
+	return resp.Game, nil
 }
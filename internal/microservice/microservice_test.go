@@ -0,0 +1,135 @@
+package microservice
+
+import (
+	"context"
+	"errors"
+	"social-service/internal/cache"
+	"social-service/internal/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	authpb "github.com/viktoralyoshin/playhub-proto/gen/go/auth"
+	gamepb "github.com/viktoralyoshin/playhub-proto/gen/go/games"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type MockAuthClient struct {
+	mock.Mock
+	authpb.AuthServiceClient
+}
+
+func (m *MockAuthClient) GetUser(ctx context.Context, in *authpb.GetUserRequest, opts ...grpc.CallOption) (*authpb.GetUserResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*authpb.GetUserResponse), args.Error(1)
+}
+
+type MockGamesClient struct {
+	mock.Mock
+	gamepb.GameServiceClient
+}
+
+func (m *MockGamesClient) GetGame(ctx context.Context, in *gamepb.GetGameRequest, opts ...grpc.CallOption) (*gamepb.GetGameResponse, error) {
+	args := m.Called(ctx, in)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*gamepb.GetGameResponse), args.Error(1)
+}
+
+func TestUserClient_GetUser(t *testing.T) {
+	client := new(MockAuthClient)
+	users := NewUserClient(client)
+
+	client.On("GetUser", mock.Anything, &authpb.GetUserRequest{UserId: "u1"}).
+		Return(&authpb.GetUserResponse{UserId: "u1"}, nil).Once()
+
+	user, err := users.GetUser(context.Background(), "u1")
+	assert.NoError(t, err)
+	assert.Equal(t, "u1", user.UserId)
+}
+
+func TestGameClient_GetGame(t *testing.T) {
+	client := new(MockGamesClient)
+	games := NewGameClient(client)
+
+	t.Run("found", func(t *testing.T) {
+		client.On("GetGame", mock.Anything, mock.MatchedBy(func(req *gamepb.GetGameRequest) bool {
+			return req.GetGameId() == "g1"
+		})).Return(&gamepb.GetGameResponse{Game: &gamepb.Game{Id: "g1"}}, nil).Once()
+
+		game, err := games.GetGame(context.Background(), "g1")
+		assert.NoError(t, err)
+		assert.Equal(t, "g1", game.Id)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		client.On("GetGame", mock.Anything, mock.Anything).Return(nil, errors.New("unavailable")).Once()
+
+		_, err := games.GetGame(context.Background(), "g2")
+		assert.Error(t, err)
+	})
+}
+
+func TestConnect(t *testing.T) {
+	conns, err := Connect(&config.Config{GameServiceAddr: "localhost:1", AuthServiceAddr: "localhost:2"})
+	assert.NoError(t, err)
+	assert.NoError(t, conns.Close())
+}
+
+func TestCachedGameClient(t *testing.T) {
+	client := new(MockGamesClient)
+	games := NewCachedGameClient(NewGameClient(client), cache.Options{Capacity: 10, TTL: time.Minute, NegativeTTL: time.Minute})
+
+	client.On("GetGame", mock.Anything, mock.MatchedBy(func(req *gamepb.GetGameRequest) bool {
+		return req.GetGameId() == "g1"
+	})).Return(&gamepb.GetGameResponse{Game: &gamepb.Game{Id: "g1"}}, nil).Twice()
+	client.On("GetGame", mock.Anything, mock.MatchedBy(func(req *gamepb.GetGameRequest) bool {
+		return req.GetGameId() == "missing"
+	})).Return(nil, status.Error(codes.NotFound, "no such game")).Once()
+	client.On("GetGame", mock.Anything, mock.MatchedBy(func(req *gamepb.GetGameRequest) bool {
+		return req.GetGameId() == "flaky"
+	})).Return(nil, status.Error(codes.Unavailable, "down")).Twice()
+
+	for range 3 {
+		game, err := games.GetGame(context.Background(), "g1")
+		assert.NoError(t, err)
+		assert.Equal(t, "g1", game.Id)
+
+		_, err = games.GetGame(context.Background(), "missing")
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	}
+
+	for range 2 {
+		_, err := games.GetGame(context.Background(), "flaky")
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+
+	games.Invalidate("g1")
+	_, err := games.GetGame(context.Background(), "g1")
+	assert.NoError(t, err)
+
+	client.AssertExpectations(t)
+}
+
+func TestCachedUserClient(t *testing.T) {
+	client := new(MockAuthClient)
+	users := NewCachedUserClient(NewUserClient(client), cache.Options{Capacity: 10, TTL: time.Minute, NegativeTTL: time.Minute})
+
+	client.On("GetUser", mock.Anything, &authpb.GetUserRequest{UserId: "u1"}).
+		Return(&authpb.GetUserResponse{UserId: "u1"}, nil).Once()
+
+	for range 3 {
+		user, err := users.GetUser(context.Background(), "u1")
+		assert.NoError(t, err)
+		assert.Equal(t, "u1", user.UserId)
+	}
+
+	client.AssertExpectations(t)
+}
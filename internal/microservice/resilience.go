@@ -0,0 +1,193 @@
+package microservice
+
+import (
+	"context"
+	"math/rand/v2"
+	"social-service/internal/metrics"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryPolicy bounds a downstream call. Timeout applies to each attempt;
+// the caller's deadline still caps the call as a whole.
+type RetryPolicy struct {
+	Timeout     time.Duration
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// backoff returns a full-jitter delay before the given retry (1-based).
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	limit := p.MaxBackoff
+	if shifted := p.Backoff << (retry - 1); shifted > 0 && shifted < limit {
+		limit = shifted
+	}
+	if limit <= 0 {
+		return 0
+	}
+
+	return rand.N(limit) + 1
+}
+
+// retryable codes mean the call did not reach a healthy server and the
+// lookups, which are reads, can be sent again.
+func retryable(code codes.Code) bool {
+	switch code {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// Breaker stops calling a downstream after consecutive failures. Once the
+// cooldown elapses a single probe is let through: success closes the
+// breaker, failure opens it again.
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func NewBreaker(name string, threshold int, cooldown time.Duration) *Breaker {
+	metrics.SetCircuitOpen(name, false)
+
+	return &Breaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+// Allow reports whether a call may be sent now.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	case breakerHalfOpen:
+		// The probe is still in flight.
+		return false
+	default:
+		return true
+	}
+}
+
+// Record reports the outcome of a call let through by Allow.
+func (b *Breaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !failed {
+		if b.state != breakerClosed {
+			log.Info().Str("downstream", b.name).Msg("circuit breaker closed")
+			metrics.SetCircuitOpen(b.name, false)
+		}
+		b.state, b.failures = breakerClosed, 0
+		return
+	}
+
+	b.failures++
+	if b.state == breakerHalfOpen || b.failures >= b.threshold {
+		if b.state != breakerOpen {
+			log.Warn().Str("downstream", b.name).Int("failures", b.failures).Dur("cooldown", b.cooldown).Msg("circuit breaker opened")
+			metrics.SetCircuitOpen(b.name, true)
+		}
+		b.state, b.openedAt = breakerOpen, b.now()
+	}
+}
+
+// Cancel hands back a call let through by Allow whose outcome is unknown,
+// so a half-open breaker lets the next call probe instead.
+func (b *Breaker) Cancel() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
+
+// ResilienceInterceptor applies policy to every call on a connection and
+// guards it with breaker. Calls rejected by an open breaker fail fast with
+// Unavailable.
+func ResilienceInterceptor(name string, policy RetryPolicy, breaker *Breaker) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		var err error
+
+		for attempt := 1; ; attempt++ {
+			if !breaker.Allow() {
+				return status.Errorf(codes.Unavailable, "%s: circuit breaker is open", name)
+			}
+
+			err = invokeOnce(ctx, policy.Timeout, method, req, reply, cc, invoker, opts...)
+
+			code := status.Code(err)
+			// The caller giving up says nothing about the downstream.
+			if ctx.Err() != nil {
+				breaker.Cancel()
+				return err
+			}
+			breaker.Record(code == codes.Unavailable || code == codes.DeadlineExceeded)
+
+			if err == nil || !retryable(code) || attempt >= policy.MaxAttempts {
+				return err
+			}
+
+			delay := policy.backoff(attempt)
+			log.Ctx(ctx).Debug().
+				Err(err).
+				Str("downstream", name).
+				Str("method", method).
+				Int("attempt", attempt).
+				Dur("backoff", delay).
+				Msg("retrying downstream call")
+			metrics.ObserveRetry(name)
+
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return err
+			case <-timer.C:
+			}
+		}
+	}
+}
+
+func invokeOnce(ctx context.Context, timeout time.Duration, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	return invoker(ctx, method, req, reply, cc, opts...)
+}
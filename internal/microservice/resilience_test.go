@@ -0,0 +1,127 @@
+package microservice
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var testPolicy = RetryPolicy{
+	Timeout:     50 * time.Millisecond,
+	MaxAttempts: 3,
+	Backoff:     time.Millisecond,
+	MaxBackoff:  2 * time.Millisecond,
+}
+
+// scriptedInvoker returns the given errors in order and counts the calls.
+func scriptedInvoker(calls *int, errs ...error) grpc.UnaryInvoker {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		err := errs[min(*calls, len(errs)-1)]
+		*calls++
+		return err
+	}
+}
+
+func TestResilienceInterceptor_Retries(t *testing.T) {
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCode  codes.Code
+		wantCalls int
+	}{
+		{"success", []error{nil}, codes.OK, 1},
+		{"transient then success", []error{status.Error(codes.Unavailable, "down"), nil}, codes.OK, 2},
+		{"gives up after max attempts", []error{status.Error(codes.Unavailable, "down")}, codes.Unavailable, 3},
+		{"not found is final", []error{status.Error(codes.NotFound, "missing")}, codes.NotFound, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interceptor := ResilienceInterceptor("games-service", testPolicy, NewBreaker("games-service", 10, time.Minute))
+
+			calls := 0
+			err := interceptor(context.Background(), "/GetGame", nil, nil, nil, scriptedInvoker(&calls, tt.errs...))
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCalls, calls)
+		})
+	}
+}
+
+func TestResilienceInterceptor_AttemptTimeout(t *testing.T) {
+	interceptor := ResilienceInterceptor("games-service", testPolicy, NewBreaker("games-service", 10, time.Minute))
+
+	calls := 0
+	err := interceptor(context.Background(), "/GetGame", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			if calls == 1 {
+				<-ctx.Done()
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			return nil
+		})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls, "a slow attempt is cut off and retried")
+}
+
+func TestResilienceInterceptor_CallerCancelled(t *testing.T) {
+	breaker := NewBreaker("games-service", 1, time.Minute)
+	interceptor := ResilienceInterceptor("games-service", testPolicy, breaker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := interceptor(ctx, "/GetGame", nil, nil, nil,
+		func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			calls++
+			cancel()
+			return status.Error(codes.Canceled, "context canceled")
+		})
+
+	assert.Equal(t, codes.Canceled, status.Code(err))
+	assert.Equal(t, 1, calls)
+	assert.True(t, breaker.Allow(), "cancellations do not trip the breaker")
+}
+
+func TestBreaker(t *testing.T) {
+	now := time.Now()
+	breaker := NewBreaker("auth-service", 2, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	policy := testPolicy
+	policy.MaxAttempts = 1
+	interceptor := ResilienceInterceptor("auth-service", policy, breaker)
+
+	calls := 0
+	down := scriptedInvoker(&calls, status.Error(codes.Unavailable, "down"))
+
+	for range 2 {
+		err := interceptor(context.Background(), "/GetUser", nil, nil, nil, down)
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	}
+	assert.Equal(t, 2, calls)
+
+	err := interceptor(context.Background(), "/GetUser", nil, nil, nil, down)
+	assert.ErrorContains(t, err, "circuit breaker is open")
+	assert.Equal(t, 2, calls, "an open breaker fails fast")
+
+	// After the cooldown a single probe goes through; its failure reopens.
+	now = now.Add(time.Minute)
+	assert.True(t, breaker.Allow())
+	assert.False(t, breaker.Allow(), "only one probe while half-open")
+	breaker.Record(true)
+	assert.False(t, breaker.Allow())
+
+	now = now.Add(time.Minute)
+	calls = 0
+	err = interceptor(context.Background(), "/GetUser", nil, nil, nil, scriptedInvoker(&calls, nil))
+	assert.NoError(t, err)
+	assert.True(t, breaker.Allow(), "a successful probe closes the breaker")
+}
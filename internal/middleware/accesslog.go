@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// AccessLogInterceptor writes one line per call with its method, status code
+// and duration. Server-side failures are logged at error level.
+func AccessLogInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+
+		log.Ctx(ctx).WithLevel(accessLogLevel(code)).
+			Str("method", info.FullMethod).
+			Str("code", code.String()).
+			Dur("duration", time.Since(start)).
+			Msg("grpc request")
+
+		return resp, err
+	}
+}
+
+func accessLogLevel(code codes.Code) zerolog.Level {
+	switch code {
+	case codes.OK:
+		return zerolog.InfoLevel
+	case codes.Internal, codes.Unknown, codes.DataLoss, codes.Unavailable, codes.DeadlineExceeded:
+		return zerolog.ErrorLevel
+	default:
+		return zerolog.WarnLevel
+	}
+}
@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"context"
+	"social-service/internal/auth"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	UserIDHeader   = "x-user-id"
+	UserRoleHeader = "x-user-role"
+)
+
+// TokenVerifier validates a bearer token and returns its claims.
+type TokenVerifier interface {
+	Verify(token string) (*auth.Claims, error)
+}
+
+// AuthInterceptor replaces the caller-supplied x-user-id and x-user-role
+// headers with the identity in a verified bearer token, so handlers keep
+// reading them through utils.GetUserID. Calls without a token continue
+// anonymously; an invalid token is rejected with Unauthenticated.
+//
+// Without this interceptor the headers are trusted as set by the gateway.
+func AuthInterceptor(verifier TokenVerifier) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		md = md.Copy()
+		md.Delete(UserIDHeader)
+		md.Delete(UserRoleHeader)
+
+		token, present, ok := bearerToken(md)
+		if present {
+			if !ok {
+				return nil, status.Error(codes.Unauthenticated, "malformed authorization header")
+			}
+
+			claims, err := verifier.Verify(token)
+			if err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("rejected bearer token")
+				return nil, status.Error(codes.Unauthenticated, "invalid bearer token")
+			}
+
+			md.Set(UserIDHeader, claims.User())
+			if claims.UserRole != "" {
+				md.Set(UserRoleHeader, claims.UserRole)
+			}
+
+			logger := log.Ctx(ctx).With().Str("user_id", claims.User()).Logger()
+			ctx = auth.WithUser(logger.WithContext(ctx), claims.User())
+		}
+
+		return handler(metadata.NewIncomingContext(ctx, md), req)
+	}
+}
+
+// bearerToken reports whether an authorization header was sent and whether
+// it carried a bearer token.
+func bearerToken(md metadata.MD) (token string, present, ok bool) {
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", false, false
+	}
+
+	scheme, token, found := strings.Cut(values[0], " ")
+	if !found || !strings.EqualFold(scheme, "bearer") || strings.TrimSpace(token) == "" {
+		return "", true, false
+	}
+
+	return strings.TrimSpace(token), true, true
+}
@@ -0,0 +1,231 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"social-service/internal/auth"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/CreateReview"}
+
+// captureLogs routes context loggers to a buffer for the duration of the test.
+func captureLogs() (context.Context, *bytes.Buffer) {
+	buf := &bytes.Buffer{}
+	logger := zerolog.New(buf)
+
+	return logger.WithContext(context.Background()), buf
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	interceptor := RequestIDInterceptor()
+
+	t.Run("propagated", func(t *testing.T) {
+		ctx, buf := captureLogs()
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(RequestIDHeader, "req-1"))
+
+		_, err := interceptor(ctx, nil, testInfo, func(ctx context.Context, req any) (any, error) {
+			assert.Equal(t, "req-1", RequestID(ctx))
+			log.Ctx(ctx).Info().Msg("inside handler")
+			return nil, nil
+		})
+		require.NoError(t, err)
+
+		var line map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+		assert.Equal(t, "req-1", line["request_id"])
+	})
+
+	t.Run("generated", func(t *testing.T) {
+		ctx, _ := captureLogs()
+
+		_, err := interceptor(ctx, nil, testInfo, func(ctx context.Context, req any) (any, error) {
+			assert.Len(t, RequestID(ctx), 36)
+			return nil, nil
+		})
+		require.NoError(t, err)
+	})
+}
+
+func TestRequestIDClientInterceptor(t *testing.T) {
+	interceptor := RequestIDClientInterceptor()
+	ctx := context.WithValue(context.Background(), requestIDKey{}, "req-2")
+
+	err := interceptor(ctx, "/games.GameService/GetGame", nil, nil, nil, func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Equal(t, []string{"req-2"}, md.Get(RequestIDHeader))
+		return nil
+	})
+	assert.NoError(t, err)
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	ctx, buf := captureLogs()
+
+	resp, err := RecoveryInterceptor()(ctx, nil, testInfo, func(ctx context.Context, req any) (any, error) {
+		panic("boom")
+	})
+
+	assert.Nil(t, resp)
+	assert.Equal(t, codes.Internal, status.Code(err))
+	assert.Contains(t, buf.String(), "boom")
+}
+
+func TestAccessLogInterceptor(t *testing.T) {
+	interceptor := AccessLogInterceptor()
+
+	tests := []struct {
+		name  string
+		err   error
+		code  string
+		level string
+	}{
+		{"ok", nil, "OK", "info"},
+		{"client error", status.Error(codes.NotFound, "missing"), "NotFound", "warn"},
+		{"server error", errors.New("plain error"), "Unknown", "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, buf := captureLogs()
+
+			_, err := interceptor(ctx, nil, testInfo, func(ctx context.Context, req any) (any, error) {
+				return nil, tt.err
+			})
+			assert.Equal(t, tt.err, err)
+
+			var line map[string]any
+			require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+			assert.Equal(t, tt.code, line["code"])
+			assert.Equal(t, tt.level, line["level"])
+			assert.Equal(t, testInfo.FullMethod, line["method"])
+			assert.Contains(t, line, "duration")
+		})
+	}
+}
+
+type fakeVerifier map[string]*auth.Claims
+
+func (f fakeVerifier) Verify(token string) (*auth.Claims, error) {
+	if claims, ok := f[token]; ok {
+		return claims, nil
+	}
+
+	return nil, errors.New("bad signature")
+}
+
+func TestAuthInterceptor(t *testing.T) {
+	interceptor := AuthInterceptor(fakeVerifier{
+		"good":  {UserID: "user-1", UserRole: "admin"},
+		"other": {RegisteredClaims: jwt.RegisteredClaims{Subject: "user-2"}},
+	})
+
+	identity := func(ctx context.Context) (string, string) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		return strings.Join(md.Get(UserIDHeader), ","), strings.Join(md.Get(UserRoleHeader), ",")
+	}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantCode codes.Code
+		wantID   string
+		wantRole string
+	}{
+		{
+			name:     "valid token replaces spoofed headers",
+			md:       metadata.Pairs("authorization", "Bearer good", UserIDHeader, "victim", UserRoleHeader, "user"),
+			wantID:   "user-1",
+			wantRole: "admin",
+		},
+		{
+			name:   "subject is used without user_id",
+			md:     metadata.Pairs("authorization", "bearer other"),
+			wantID: "user-2",
+		},
+		{
+			name: "anonymous call drops spoofed headers",
+			md:   metadata.Pairs(UserIDHeader, "victim", UserRoleHeader, "admin"),
+		},
+		{
+			name:     "invalid token",
+			md:       metadata.Pairs("authorization", "Bearer forged"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "not a bearer token",
+			md:       metadata.Pairs("authorization", "Basic dXNlcjpwYXNz"),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			called := false
+			_, err := interceptor(ctx, nil, testInfo, func(ctx context.Context, req any) (any, error) {
+				called = true
+				id, role := identity(ctx)
+				assert.Equal(t, tt.wantID, id)
+				assert.Equal(t, tt.wantRole, role)
+				verified, _ := auth.UserFromContext(ctx)
+				assert.Equal(t, tt.wantID, verified)
+				return nil, nil
+			})
+
+			assert.Equal(t, tt.wantCode, status.Code(err))
+			assert.Equal(t, tt.wantCode == codes.OK, called)
+		})
+	}
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *fakeServerStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	ctx, buf := captureLogs()
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(RequestIDHeader, "req-3"))
+	info := &grpc.StreamServerInfo{FullMethod: "/reviews.ReviewService/StreamReviews", IsServerStream: true}
+
+	interceptor := StreamInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return RequestIDInterceptor()(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return RecoveryInterceptor()(ctx, req, info, handler)
+		})
+	})
+
+	t.Run("handler sees the chain's context", func(t *testing.T) {
+		err := interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(srv any, stream grpc.ServerStream) error {
+			assert.Equal(t, "req-3", RequestID(stream.Context()))
+			return status.Error(codes.OutOfRange, "gone")
+		})
+		assert.Equal(t, codes.OutOfRange, status.Code(err))
+	})
+
+	t.Run("panics are recovered", func(t *testing.T) {
+		err := interceptor(nil, &fakeServerStream{ctx: ctx}, info, func(srv any, stream grpc.ServerStream) error {
+			panic("stream boom")
+		})
+		assert.Equal(t, codes.Internal, status.Code(err))
+		assert.Contains(t, buf.String(), "/reviews.ReviewService/StreamReviews")
+	})
+}
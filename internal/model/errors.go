@@ -0,0 +1,17 @@
+package model
+
+import "errors"
+
+var (
+	ErrReviewNotFound        = errors.New("review not found")
+	ErrReviewStatusUnchanged = errors.New("review already has this status")
+	ErrReviewNotRemoved      = errors.New("review is not removed")
+	ErrNotReviewAuthor       = errors.New("only the review author can appeal")
+	ErrNotReviewEditor       = errors.New("only the review author can edit it")
+	ErrReviewRemoved         = errors.New("review has been removed")
+	ErrReviewVersionConflict = errors.New("review was modified since the given version")
+	ErrAppealExists          = errors.New("review already appealed")
+	ErrAppealNotFound        = errors.New("appeal not found")
+	ErrAppealResolved        = errors.New("appeal already resolved")
+	ErrSameModerator         = errors.New("appeal must be resolved by a different moderator")
+)
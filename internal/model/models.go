@@ -1,11 +1,18 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// Ratings are bounded by the CHECK constraint on social.reviews.
+const (
+	MinRating = 0
+	MaxRating = 100
+)
+
 type Review struct {
 	Id        uuid.UUID `json:"id"`
 	UserID    uuid.UUID `json:"user_id"`
@@ -14,4 +21,104 @@ type Review struct {
 	Text      string    `json:"text"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
+	// Version starts at 1 and is bumped by every edit of the review.
+	Version int `json:"version"`
+}
+
+type RatingStats struct {
+	Count     int     `json:"count"`
+	Average   float64 `json:"average"`
+	LowRating int     `json:"low_rating"`
+}
+
+type RatingSummary struct {
+	GameID     uuid.UUID `json:"game_id"`
+	Average    float64   `json:"average"`
+	Count      int       `json:"count"`
+	ReviewBomb bool      `json:"review_bomb"`
+}
+
+type ReviewBombPeriod struct {
+	Id              uuid.UUID  `json:"id"`
+	GameID          uuid.UUID  `json:"game_id"`
+	StartedAt       time.Time  `json:"started_at"`
+	EndedAt         *time.Time `json:"ended_at"`
+	WindowReviews   int        `json:"window_reviews"`
+	WindowAverage   float64    `json:"window_average"`
+	BaselineReviews int        `json:"baseline_reviews"`
+	BaselineAverage float64    `json:"baseline_average"`
+	CreatedAt       time.Time  `json:"created_at"`
+}
+
+type ShadowBan struct {
+	UserID    uuid.UUID `json:"user_id"`
+	Reason    string    `json:"reason"`
+	BannedBy  uuid.UUID `json:"banned_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const (
+	ReviewStatusPublished = "published"
+	ReviewStatusHidden    = "hidden"
+	ReviewStatusDeleted   = "deleted"
+)
+
+type ModeratedReview struct {
+	Review
+	Status string `json:"status"`
+}
+
+const (
+	ModerationActionHideReview    = "hide_review"
+	ModerationActionDeleteReview  = "delete_review"
+	ModerationActionRestoreReview = "restore_review"
+	ModerationActionShadowBan     = "shadow_ban"
+	ModerationActionLiftShadowBan = "lift_shadow_ban"
+	ModerationActionResolveAppeal = "resolve_appeal"
+
+	ModerationTargetReview = "review"
+	ModerationTargetUser   = "user"
+	ModerationTargetAppeal = "appeal"
+)
+
+type ModerationLogEntry struct {
+	Id         uuid.UUID       `json:"id"`
+	ActorID    uuid.UUID       `json:"actor_id"`
+	Action     string          `json:"action"`
+	TargetType string          `json:"target_type"`
+	TargetID   uuid.UUID       `json:"target_id"`
+	Reason     string          `json:"reason"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+type ModerationLogFilter struct {
+	ActorID    *uuid.UUID
+	TargetID   *uuid.UUID
+	TargetType string
+	Action     string
+	From       *time.Time
+	To         *time.Time
+	Limit      int32
+	Offset     int32
+}
+
+const (
+	AppealStatusPending    = "pending"
+	AppealStatusUpheld     = "upheld"
+	AppealStatusReinstated = "reinstated"
+)
+
+type Appeal struct {
+	Id             uuid.UUID  `json:"id"`
+	ReviewID       uuid.UUID  `json:"review_id"`
+	UserID         uuid.UUID  `json:"user_id"`
+	Message        string     `json:"message"`
+	Status         string     `json:"status"`
+	RemovedBy      *uuid.UUID `json:"removed_by"`
+	ResolvedBy     *uuid.UUID `json:"resolved_by"`
+	ResolutionNote string     `json:"resolution_note"`
+	CreatedAt      time.Time  `json:"created_at"`
+	ResolvedAt     *time.Time `json:"resolved_at"`
 }
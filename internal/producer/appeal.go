@@ -0,0 +1,86 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"social-service/internal/metrics"
+	"social-service/internal/model"
+	"social-service/internal/tracing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel/codes"
+)
+
+type AppealPublisher interface {
+	PublishResolution(ctx context.Context, appeal *model.Appeal) error
+}
+
+type AppealEvent struct {
+	AppealID   string    `json:"appeal_id"`
+	ReviewID   string    `json:"review_id"`
+	UserID     string    `json:"user_id"`
+	Outcome    string    `json:"outcome"`
+	Note       string    `json:"note,omitempty"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
+
+type AppealProducer struct {
+	writer KafkaWriter
+	topic  string
+}
+
+func NewAppealProducer(broker string, topic string) *AppealProducer {
+	return &AppealProducer{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(broker),
+			Topic:    topic,
+			Balancer: &kafka.Hash{},
+			Async:    false,
+		},
+		topic: topic,
+	}
+}
+
+// PublishResolution notifies about a resolved appeal. Messages are keyed by
+// the author so that notifications for one user keep their order.
+func (p *AppealProducer) PublishResolution(ctx context.Context, appeal *model.Appeal) error {
+	event := &AppealEvent{
+		AppealID: appeal.Id.String(),
+		ReviewID: appeal.ReviewID.String(),
+		UserID:   appeal.UserID.String(),
+		Outcome:  appeal.Status,
+		Note:     appeal.ResolutionNote,
+	}
+
+	if appeal.ResolvedAt != nil {
+		event.ResolvedAt = *appeal.ResolvedAt
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	ctx, span := startSpan(ctx, "AppealProducer.PublishResolution", p.topic)
+	defer span.End()
+
+	msg := kafka.Message{
+		Key:   []byte(event.UserID),
+		Value: body,
+	}
+	tracing.InjectKafka(ctx, &msg)
+
+	err = p.writer.WriteMessages(ctx, msg)
+	metrics.ObservePublish(p.topic, err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
+func (p *AppealProducer) Close() error {
+	return p.writer.Close()
+}
@@ -0,0 +1,63 @@
+package producer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAppealProducer_PublishResolution(t *testing.T) {
+	resolvedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	appeal := &model.Appeal{
+		Id:             uuid.New(),
+		ReviewID:       uuid.New(),
+		UserID:         uuid.New(),
+		Status:         model.AppealStatusReinstated,
+		ResolutionNote: "fair",
+		ResolvedAt:     &resolvedAt,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mockWriter := new(MockKafkaWriter)
+		producer := &AppealProducer{writer: mockWriter}
+
+		var event AppealEvent
+		mockWriter.On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+			return len(msgs) == 1 &&
+				string(msgs[0].Key) == appeal.UserID.String() &&
+				json.Unmarshal(msgs[0].Value, &event) == nil
+		})).Return(nil).Once()
+
+		err := producer.PublishResolution(context.Background(), appeal)
+
+		assert.NoError(t, err)
+		assert.Equal(t, appeal.Id.String(), event.AppealID)
+		assert.Equal(t, "reinstated", event.Outcome)
+		assert.Equal(t, resolvedAt, event.ResolvedAt)
+	})
+
+	t.Run("kafka write error", func(t *testing.T) {
+		mockWriter := new(MockKafkaWriter)
+		producer := &AppealProducer{writer: mockWriter}
+
+		mockWriter.On("WriteMessages", mock.Anything, mock.Anything).Return(errors.New("connection reset")).Once()
+
+		assert.Error(t, producer.PublishResolution(context.Background(), appeal))
+	})
+}
+
+func TestNewAppealProducer(t *testing.T) {
+	p := NewAppealProducer("localhost:9092", "test-topic")
+	assert.NotNil(t, p)
+	assert.NotNil(t, p.writer)
+
+	_ = p.Close()
+}
@@ -3,26 +3,39 @@ package producer
 import (
 	"context"
 	"encoding/json"
+	"social-service/internal/metrics"
+	"social-service/internal/model"
+	"social-service/internal/tracing"
 
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("social-service/internal/producer")
+
 type KafkaWriter interface {
 	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
 	Close() error
 }
 
 type RatingPublisher interface {
-	Publish(ctx context.Context, gameID uuid.UUID) error
+	Publish(ctx context.Context, gameID uuid.UUID, summary *model.RatingSummary) error
 }
 
 type ReviewEvent struct {
-	GameID string `json:"game_id"`
+	GameID        string   `json:"game_id"`
+	AverageRating *float64 `json:"average_rating,omitempty"`
+	ReviewCount   *int     `json:"review_count,omitempty"`
+	ReviewBomb    bool     `json:"review_bomb,omitempty"`
 }
 
 type RatingProducer struct {
 	writer KafkaWriter
+	topic  string
 }
 
 func NewRatingProducer(broker string, topic string) *RatingProducer {
@@ -33,28 +46,55 @@ func NewRatingProducer(broker string, topic string) *RatingProducer {
 			Balancer: &kafka.LeastBytes{},
 			Async:    false,
 		},
+		topic: topic,
 	}
 }
 
-func (p *RatingProducer) Publish(ctx context.Context, gameId uuid.UUID) error {
+func (p *RatingProducer) Publish(ctx context.Context, gameId uuid.UUID, summary *model.RatingSummary) error {
 	event := &ReviewEvent{
 		GameID: gameId.String(),
 	}
 
+	if summary != nil {
+		event.AverageRating = &summary.Average
+		event.ReviewCount = &summary.Count
+		event.ReviewBomb = summary.ReviewBomb
+	}
+
 	body, err := json.Marshal(event)
 	if err != nil {
 		return err
 	}
 
-	if err := p.writer.WriteMessages(ctx, kafka.Message{
+	ctx, span := startSpan(ctx, "RatingProducer.Publish", p.topic)
+	defer span.End()
+
+	msg := kafka.Message{
 		Value: body,
-	}); err != nil {
-		return err
 	}
+	tracing.InjectKafka(ctx, &msg)
 
-	return nil
+	err = p.writer.WriteMessages(ctx, msg)
+	metrics.ObservePublish(p.topic, err)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
 }
 
 func (p *RatingProducer) Close() error {
 	return p.writer.Close()
 }
+
+func startSpan(ctx context.Context, name string, topic string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			semconv.MessagingSystemKafka,
+			semconv.MessagingDestinationName(topic),
+			semconv.MessagingOperationTypeSend,
+		),
+	)
+}
@@ -2,13 +2,20 @@ package producer
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
+	"social-service/internal/model"
+	"social-service/internal/tracing"
 	"testing"
 
 	"github.com/google/uuid"
 	"github.com/segmentio/kafka-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type MockKafkaWriter struct {
@@ -33,16 +40,60 @@ func TestRatingProducer_Publish(t *testing.T) {
 		gameID := uuid.New()
 		ctx := context.Background()
 
-		mockWriter.On("WriteMessages", ctx, mock.MatchedBy(func(msgs []kafka.Message) bool {
+		mockWriter.On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
 			return len(msgs) == 1 && assert.Contains(t, string(msgs[0].Value), gameID.String())
 		})).Return(nil).Once()
 
-		err := producer.Publish(ctx, gameID)
+		err := producer.Publish(ctx, gameID, nil)
 
 		assert.NoError(t, err)
 		mockWriter.AssertExpectations(t)
 	})
 
+	t.Run("with rating summary", func(t *testing.T) {
+		mockWriter := new(MockKafkaWriter)
+		producer := &RatingProducer{writer: mockWriter}
+
+		gameID := uuid.New()
+		summary := &model.RatingSummary{GameID: gameID, Average: 42.5, Count: 8, ReviewBomb: true}
+
+		var event ReviewEvent
+		mockWriter.On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+			return len(msgs) == 1 && json.Unmarshal(msgs[0].Value, &event) == nil
+		})).Return(nil).Once()
+
+		err := producer.Publish(context.Background(), gameID, summary)
+
+		assert.NoError(t, err)
+		assert.Equal(t, gameID.String(), event.GameID)
+		assert.Equal(t, 42.5, *event.AverageRating)
+		assert.Equal(t, 8, *event.ReviewCount)
+		assert.True(t, event.ReviewBomb)
+	})
+
+	t.Run("propagates trace context", func(t *testing.T) {
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		otel.SetTracerProvider(sdktrace.NewTracerProvider())
+		defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+		mockWriter := new(MockKafkaWriter)
+		producer := &RatingProducer{writer: mockWriter, topic: "review_events"}
+
+		ctx, span := otel.Tracer("test").Start(context.Background(), "CreateReview")
+		defer span.End()
+
+		var headers []kafka.Header
+		mockWriter.On("WriteMessages", mock.Anything, mock.MatchedBy(func(msgs []kafka.Message) bool {
+			headers = msgs[0].Headers
+			return true
+		})).Return(nil).Once()
+
+		assert.NoError(t, producer.Publish(ctx, uuid.New(), nil))
+
+		traceparent := tracing.KafkaHeaderCarrier{Headers: &headers}.Get("traceparent")
+		assert.Contains(t, traceparent, span.SpanContext().TraceID().String())
+	})
+
 	t.Run("kafka write error", func(t *testing.T) {
 		mockWriter := new(MockKafkaWriter)
 		producer := &RatingProducer{writer: mockWriter}
@@ -51,7 +102,7 @@ func TestRatingProducer_Publish(t *testing.T) {
 		mockWriter.On("WriteMessages", mock.Anything, mock.Anything).
 			Return(errors.New("connection reset")).Once()
 
-		err := producer.Publish(context.Background(), gameID)
+		err := producer.Publish(context.Background(), gameID, nil)
 
 		assert.Error(t, err)
 		assert.Equal(t, "connection reset", err.Error())
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"net"
+	"path"
+	"social-service/internal/auth"
+	"social-service/internal/utils"
+	"strconv"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const RetryAfterHeader = "retry-after"
+
+// UnaryServerInterceptor enforces limits keyed by RPC name, e.g.
+// "CreateReview". RPCs without a configured limit are not throttled.
+func UnaryServerInterceptor(store Store, limits map[string]Limit) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		method := path.Base(info.FullMethod)
+
+		limit, ok := limits[method]
+		if !ok || limit.Rate <= 0 {
+			return handler(ctx, req)
+		}
+
+		caller := callerKey(ctx)
+
+		allowed, retryAfter, err := store.Allow(ctx, method+"|"+caller, limit)
+		if err != nil {
+			log.Ctx(ctx).Error().
+				Err(err).
+				Str("method", method).
+				Str("caller", caller).
+				Msg("ratelimit: limiter store failed, allowing request")
+			return handler(ctx, req)
+		}
+
+		if !allowed {
+			seconds := int64(math.Ceil(retryAfter.Seconds()))
+			if err := grpc.SetHeader(ctx, metadata.Pairs(RetryAfterHeader, strconv.FormatInt(seconds, 10))); err != nil {
+				log.Ctx(ctx).Debug().Err(err).Msg("ratelimit: failed to set retry-after header")
+			}
+
+			log.Ctx(ctx).Warn().
+				Str("method", method).
+				Str("caller", caller).
+				Int64("retry_after", seconds).
+				Msg("ratelimit: request throttled")
+
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// callerKey buckets callers by the user verified from their token, else by
+// the x-user-id the API gateway sets in gateway auth mode, else by peer IP.
+// In gateway mode every request comes from the gateway's IP, so keying on
+// the header is what keeps users from sharing one bucket.
+func callerKey(ctx context.Context) string {
+	if userId, ok := auth.UserFromContext(ctx); ok {
+		return "user:" + userId
+	}
+
+	if userId, err := utils.GetUserID(ctx); err == nil && userId != "" {
+		return "user:" + userId
+	}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		host, _, err := net.SplitHostPort(p.Addr.String())
+		if err != nil {
+			host = p.Addr.String()
+		}
+		return "ip:" + host
+	}
+
+	return "unknown"
+}
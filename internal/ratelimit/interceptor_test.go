@@ -0,0 +1,155 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net"
+	"social-service/internal/auth"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) Allow(ctx context.Context, key string, limit Limit) (bool, time.Duration, error) {
+	args := m.Called(ctx, key, limit)
+	return args.Bool(0), args.Get(1).(time.Duration), args.Error(2)
+}
+
+type fakeTransportStream struct {
+	grpc.ServerTransportStream
+	header metadata.MD
+}
+
+func (s *fakeTransportStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func okHandler(ctx context.Context, req any) (any, error) {
+	return "ok", nil
+}
+
+func TestCallerKey_GatewayMode(t *testing.T) {
+	gateway := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.8"), Port: 5555}})
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want string
+	}{
+		{name: "first user", md: metadata.Pairs("x-user-id", "u1"), want: "user:u1"},
+		{name: "second user", md: metadata.Pairs("x-user-id", "u2"), want: "user:u2"},
+		{name: "empty user header", md: metadata.Pairs("x-user-id", ""), want: "ip:10.0.0.8"},
+		{name: "no user header", md: metadata.MD{}, want: "ip:10.0.0.8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, callerKey(metadata.NewIncomingContext(gateway, tt.md)))
+		})
+	}
+
+	t.Run("users behind one gateway get separate buckets", func(t *testing.T) {
+		limit := Limit{Rate: 0.001, Burst: 1}
+		interceptor := UnaryServerInterceptor(NewMemoryStore(), map[string]Limit{"CreateReview": limit})
+		info := &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/CreateReview"}
+		stream := &fakeTransportStream{}
+
+		call := func(userId string) error {
+			ctx := grpc.NewContextWithServerTransportStream(gateway, stream)
+			_, err := interceptor(metadata.NewIncomingContext(ctx, metadata.Pairs("x-user-id", userId)), nil, info, okHandler)
+			return err
+		}
+
+		assert.NoError(t, call("u1"))
+		assert.Equal(t, codes.ResourceExhausted, status.Code(call("u1")))
+		assert.NoError(t, call("u2"), "u1's empty bucket does not throttle u2")
+	})
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	limit := Limit{Rate: 1, Burst: 1}
+	info := &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/CreateReview"}
+
+	t.Run("unlimited method passes through", func(t *testing.T) {
+		store := new(MockStore)
+		interceptor := UnaryServerInterceptor(store, map[string]Limit{"CreateReview": limit})
+
+		resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/GetFeed"}, okHandler)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+		store.AssertNotCalled(t, "Allow", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("keyed by authenticated user", func(t *testing.T) {
+		store := new(MockStore)
+		interceptor := UnaryServerInterceptor(store, map[string]Limit{"CreateReview": limit})
+		ctx := auth.WithUser(context.Background(), "u1")
+
+		store.On("Allow", mock.Anything, "CreateReview|user:u1", limit).Return(true, time.Duration(0), nil).Once()
+
+		_, err := interceptor(ctx, nil, info, okHandler)
+		assert.NoError(t, err)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("falls back to peer ip", func(t *testing.T) {
+		store := new(MockStore)
+		interceptor := UnaryServerInterceptor(store, map[string]Limit{"CreateReview": limit})
+		ctx := peer.NewContext(context.Background(), &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 5555}})
+
+		store.On("Allow", mock.Anything, "CreateReview|ip:10.0.0.7", limit).Return(true, time.Duration(0), nil).Once()
+
+		_, err := interceptor(ctx, nil, info, okHandler)
+		assert.NoError(t, err)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("verified user wins over user header", func(t *testing.T) {
+		store := new(MockStore)
+		interceptor := UnaryServerInterceptor(store, map[string]Limit{"CreateReview": limit})
+		ctx := auth.WithUser(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "u2")), "u1")
+
+		store.On("Allow", mock.Anything, "CreateReview|user:u1", limit).Return(true, time.Duration(0), nil).Once()
+
+		_, err := interceptor(ctx, nil, info, okHandler)
+		assert.NoError(t, err)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("throttled request", func(t *testing.T) {
+		store := new(MockStore)
+		interceptor := UnaryServerInterceptor(store, map[string]Limit{"CreateReview": limit})
+		stream := &fakeTransportStream{}
+		ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+		store.On("Allow", mock.Anything, mock.Anything, limit).Return(false, 1500*time.Millisecond, nil).Once()
+
+		resp, err := interceptor(ctx, nil, info, okHandler)
+		assert.Nil(t, resp)
+		assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+		assert.Equal(t, []string{"2"}, stream.header.Get(RetryAfterHeader))
+	})
+
+	t.Run("store failure fails open", func(t *testing.T) {
+		store := new(MockStore)
+		interceptor := UnaryServerInterceptor(store, map[string]Limit{"CreateReview": limit})
+
+		store.On("Allow", mock.Anything, mock.Anything, limit).Return(false, time.Duration(0), errors.New("redis down")).Once()
+
+		resp, err := interceptor(context.Background(), nil, info, okHandler)
+		assert.NoError(t, err)
+		assert.Equal(t, "ok", resp)
+	})
+}
@@ -0,0 +1,63 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStore_Allow(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	limit := Limit{Rate: 1, Burst: 2}
+
+	t.Run("burst is allowed", func(t *testing.T) {
+		for range 2 {
+			allowed, _, err := store.Allow(ctx, "a", limit)
+			assert.NoError(t, err)
+			assert.True(t, allowed)
+		}
+	})
+
+	t.Run("empty bucket is rejected with retry-after", func(t *testing.T) {
+		allowed, retryAfter, err := store.Allow(ctx, "a", limit)
+		assert.NoError(t, err)
+		assert.False(t, allowed)
+		assert.Equal(t, time.Second, retryAfter)
+	})
+
+	t.Run("keys are independent", func(t *testing.T) {
+		allowed, _, _ := store.Allow(ctx, "b", limit)
+		assert.True(t, allowed)
+	})
+
+	t.Run("bucket refills over time", func(t *testing.T) {
+		now = now.Add(1500 * time.Millisecond)
+		allowed, _, _ := store.Allow(ctx, "a", limit)
+		assert.True(t, allowed)
+
+		allowed, retryAfter, _ := store.Allow(ctx, "a", limit)
+		assert.False(t, allowed)
+		assert.Equal(t, 500*time.Millisecond, retryAfter)
+	})
+}
+
+func TestMemoryStore_Sweep(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+
+	_, _, _ = store.Allow(context.Background(), "idle", Limit{Rate: 1, Burst: 1})
+	_, _, _ = store.Allow(context.Background(), "busy", Limit{Rate: 0.001, Burst: 1})
+
+	now = now.Add(10 * time.Second)
+	store.sweep(now)
+
+	assert.NotContains(t, store.buckets, "idle")
+	assert.Contains(t, store.buckets, "busy")
+}
@@ -0,0 +1,221 @@
+package reviewbomb
+
+import (
+	"context"
+	"social-service/internal/model"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type Store interface {
+	GetRatingStats(ctx context.Context, gameID uuid.UUID, since, until time.Duration, lowRating int) (*model.RatingStats, error)
+	GetActivePeriod(ctx context.Context, gameID uuid.UUID) (*model.ReviewBombPeriod, error)
+	OpenPeriod(ctx context.Context, period *model.ReviewBombPeriod, window time.Duration) (*model.ReviewBombPeriod, error)
+	ClosePeriod(ctx context.Context, id uuid.UUID) error
+	GetBombedGames(ctx context.Context) ([]uuid.UUID, error)
+}
+
+type Config struct {
+	// Window is the short period whose volume is compared to the baseline.
+	Window time.Duration
+	// Baseline is the period preceding Window used as the game's normal level.
+	Baseline time.Duration
+	// MinReviews is the smallest window volume that can ever be a bomb.
+	MinReviews int
+	// RateFactor is how many times the baseline volume the window must reach.
+	RateFactor float64
+	// LowRating is the highest rating counted as a negative review.
+	LowRating int
+	// LowShare is the minimal share of negative reviews inside the window.
+	LowShare float64
+	// ExcludeFromSummary drops bombed windows from the published rating.
+	ExcludeFromSummary bool
+}
+
+type Detector struct {
+	store    Store
+	cfg      Config
+	onChange func(ctx context.Context, gameID uuid.UUID)
+
+	mu      sync.Mutex
+	pending map[uuid.UUID]struct{}
+	wake    chan struct{}
+}
+
+func NewDetector(store Store, cfg Config) *Detector {
+	return &Detector{
+		store:   store,
+		cfg:     cfg,
+		pending: make(map[uuid.UUID]struct{}),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// OnChange registers fn to be called after a game's review-bomb period opens
+// or closes. It must be called before Run.
+func (d *Detector) OnChange(fn func(ctx context.Context, gameID uuid.UUID)) {
+	d.onChange = fn
+}
+
+func (d *Detector) ExcludeFromSummary() bool {
+	return d.cfg.ExcludeFromSummary
+}
+
+// Observe queues the game of a freshly inserted review for evaluation by Run.
+// It never blocks, and a game queued several times is evaluated once.
+func (d *Detector) Observe(_ context.Context, review *model.Review) {
+	d.mu.Lock()
+	d.pending[review.GameID] = struct{}{}
+	d.mu.Unlock()
+
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run evaluates the games queued by Observe and, every interval, the games
+// with an open period, so a period closes once its window calms down even if
+// no more reviews arrive. It returns when ctx is cancelled.
+func (d *Detector) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+			d.evaluateAll(ctx, d.drain())
+		case <-ticker.C:
+			games, err := d.store.GetBombedGames(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("reviewbomb.Detector: failed to list bombed games")
+				continue
+			}
+			d.evaluateAll(ctx, games)
+		}
+	}
+}
+
+func (d *Detector) drain() []uuid.UUID {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	games := make([]uuid.UUID, 0, len(d.pending))
+	for gameID := range d.pending {
+		games = append(games, gameID)
+		delete(d.pending, gameID)
+	}
+
+	return games
+}
+
+// evaluateAll logs failures and moves on, so one game cannot stall the rest.
+func (d *Detector) evaluateAll(ctx context.Context, games []uuid.UUID) {
+	for _, gameID := range games {
+		changed, err := d.evaluate(ctx, gameID)
+		if err != nil {
+			log.Error().
+				Err(err).
+				Str("game_id", gameID.String()).
+				Msg("reviewbomb.Detector: evaluation failed")
+			continue
+		}
+
+		if changed && d.onChange != nil {
+			d.onChange(ctx, gameID)
+		}
+	}
+}
+
+// evaluate opens or closes the game's review-bomb period and reports whether
+// it did either.
+func (d *Detector) evaluate(ctx context.Context, gameID uuid.UUID) (bool, error) {
+	window, err := d.store.GetRatingStats(ctx, gameID, d.cfg.Window, 0, d.cfg.LowRating)
+	if err != nil {
+		return false, err
+	}
+
+	baseline, err := d.store.GetRatingStats(ctx, gameID, d.cfg.Window+d.cfg.Baseline, d.cfg.Window, d.cfg.LowRating)
+	if err != nil {
+		return false, err
+	}
+
+	active, err := d.store.GetActivePeriod(ctx, gameID)
+	if err != nil {
+		return false, err
+	}
+
+	bombed := d.isBomb(window, baseline)
+
+	switch {
+	case bombed && active == nil:
+		period, err := d.store.OpenPeriod(ctx, &model.ReviewBombPeriod{
+			GameID:          gameID,
+			WindowReviews:   window.Count,
+			WindowAverage:   window.Average,
+			BaselineReviews: baseline.Count,
+			BaselineAverage: baseline.Average,
+		}, d.cfg.Window)
+		if err != nil {
+			return false, err
+		}
+
+		if period == nil {
+			return false, nil
+		}
+
+		log.Warn().
+			Str("game_id", gameID.String()).
+			Int("window_reviews", window.Count).
+			Float64("window_average", window.Average).
+			Int("baseline_reviews", baseline.Count).
+			Float64("baseline_average", baseline.Average).
+			Msg("reviewbomb.Detector: review bomb detected")
+
+		return true, nil
+	case !bombed && active != nil:
+		if err := d.store.ClosePeriod(ctx, active.Id); err != nil {
+			return false, err
+		}
+
+		log.Info().
+			Str("game_id", gameID.String()).
+			Str("period_id", active.Id.String()).
+			Msg("reviewbomb.Detector: review bomb period closed")
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func (d *Detector) isBomb(window, baseline *model.RatingStats) bool {
+	if window.Count == 0 || window.Count < d.cfg.MinReviews {
+		return false
+	}
+
+	expected := float64(baseline.Count)
+	if d.cfg.Baseline > 0 {
+		expected = expected * float64(d.cfg.Window) / float64(d.cfg.Baseline)
+	}
+
+	if expected < 1 {
+		expected = 1
+	}
+
+	if float64(window.Count) < expected*d.cfg.RateFactor {
+		return false
+	}
+
+	lowShare := float64(window.LowRating) / float64(window.Count)
+	if lowShare < d.cfg.LowShare {
+		return false
+	}
+
+	return baseline.Count == 0 || window.Average < baseline.Average
+}
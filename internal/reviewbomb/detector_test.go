@@ -0,0 +1,244 @@
+package reviewbomb
+
+import (
+	"context"
+	"errors"
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type MockStore struct {
+	mock.Mock
+}
+
+func (m *MockStore) GetRatingStats(ctx context.Context, gameID uuid.UUID, since, until time.Duration, lowRating int) (*model.RatingStats, error) {
+	args := m.Called(ctx, gameID, since, until, lowRating)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.RatingStats), args.Error(1)
+}
+
+func (m *MockStore) GetActivePeriod(ctx context.Context, gameID uuid.UUID) (*model.ReviewBombPeriod, error) {
+	args := m.Called(ctx, gameID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReviewBombPeriod), args.Error(1)
+}
+
+func (m *MockStore) OpenPeriod(ctx context.Context, period *model.ReviewBombPeriod, window time.Duration) (*model.ReviewBombPeriod, error) {
+	args := m.Called(ctx, period, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*model.ReviewBombPeriod), args.Error(1)
+}
+
+func (m *MockStore) ClosePeriod(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockStore) GetBombedGames(ctx context.Context) ([]uuid.UUID, error) {
+	args := m.Called(ctx)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+var testConfig = Config{
+	Window:     time.Hour,
+	Baseline:   10 * time.Hour,
+	MinReviews: 10,
+	RateFactor: 5,
+	LowRating:  20,
+	LowShare:   0.7,
+}
+
+func setupDetectorTest(window, baseline *model.RatingStats) (*Detector, *MockStore) {
+	store := new(MockStore)
+	d := NewDetector(store, testConfig)
+
+	store.On("GetRatingStats", mock.Anything, mock.Anything, time.Hour, time.Duration(0), 20).Return(window, nil).Once()
+	store.On("GetRatingStats", mock.Anything, mock.Anything, 11*time.Hour, time.Hour, 20).Return(baseline, nil).Once()
+
+	return d, store
+}
+
+func TestDetector_Evaluate(t *testing.T) {
+	gameID := uuid.New()
+
+	t.Run("opens period on bomb", func(t *testing.T) {
+		d, store := setupDetectorTest(
+			&model.RatingStats{Count: 100, Average: 3, LowRating: 95},
+			&model.RatingStats{Count: 50, Average: 80, LowRating: 2},
+		)
+
+		store.On("GetActivePeriod", mock.Anything, gameID).Return(nil, nil).Once()
+		store.On("OpenPeriod", mock.Anything, mock.MatchedBy(func(p *model.ReviewBombPeriod) bool {
+			return p.GameID == gameID && p.WindowReviews == 100
+		}), time.Hour).Return(&model.ReviewBombPeriod{Id: uuid.New(), GameID: gameID}, nil).Once()
+
+		changed, err := d.evaluate(context.Background(), gameID)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("ignores normal volume", func(t *testing.T) {
+		d, store := setupDetectorTest(
+			&model.RatingStats{Count: 12, Average: 10, LowRating: 12},
+			&model.RatingStats{Count: 500, Average: 80, LowRating: 20},
+		)
+
+		store.On("GetActivePeriod", mock.Anything, gameID).Return(nil, nil).Once()
+
+		changed, err := d.evaluate(context.Background(), gameID)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		store.AssertExpectations(t)
+		store.AssertNotCalled(t, "OpenPeriod", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("ignores positive surge", func(t *testing.T) {
+		d, store := setupDetectorTest(
+			&model.RatingStats{Count: 100, Average: 95, LowRating: 1},
+			&model.RatingStats{Count: 10, Average: 80, LowRating: 1},
+		)
+
+		store.On("GetActivePeriod", mock.Anything, gameID).Return(nil, nil).Once()
+
+		changed, err := d.evaluate(context.Background(), gameID)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		store.AssertNotCalled(t, "OpenPeriod", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("closes period when window calms down", func(t *testing.T) {
+		d, store := setupDetectorTest(
+			&model.RatingStats{Count: 3, Average: 70, LowRating: 0},
+			&model.RatingStats{Count: 50, Average: 80, LowRating: 2},
+		)
+
+		periodID := uuid.New()
+		store.On("GetActivePeriod", mock.Anything, gameID).Return(&model.ReviewBombPeriod{Id: periodID}, nil).Once()
+		store.On("ClosePeriod", mock.Anything, periodID).Return(nil).Once()
+
+		changed, err := d.evaluate(context.Background(), gameID)
+		assert.NoError(t, err)
+		assert.True(t, changed)
+		store.AssertExpectations(t)
+	})
+
+	t.Run("keeps active period open during bomb", func(t *testing.T) {
+		d, store := setupDetectorTest(
+			&model.RatingStats{Count: 100, Average: 3, LowRating: 95},
+			&model.RatingStats{Count: 50, Average: 80, LowRating: 2},
+		)
+
+		store.On("GetActivePeriod", mock.Anything, gameID).Return(&model.ReviewBombPeriod{Id: uuid.New()}, nil).Once()
+
+		changed, err := d.evaluate(context.Background(), gameID)
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		store.AssertNotCalled(t, "OpenPeriod", mock.Anything, mock.Anything, mock.Anything)
+		store.AssertNotCalled(t, "ClosePeriod", mock.Anything, mock.Anything)
+	})
+
+	t.Run("store error is returned", func(t *testing.T) {
+		store := new(MockStore)
+		d := NewDetector(store, testConfig)
+
+		store.On("GetRatingStats", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything).
+			Return(nil, errors.New("db fail")).Once()
+
+		_, err := d.evaluate(context.Background(), gameID)
+		assert.Error(t, err)
+		store.AssertNotCalled(t, "GetActivePeriod", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDetector_Run(t *testing.T) {
+	gameID := uuid.New()
+
+	run := func(d *Detector, interval time.Duration) (chan uuid.UUID, func()) {
+		changed := make(chan uuid.UUID, 1)
+		d.OnChange(func(_ context.Context, gameID uuid.UUID) { changed <- gameID })
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			d.Run(ctx, interval)
+			close(done)
+		}()
+
+		return changed, func() {
+			cancel()
+			<-done
+		}
+	}
+
+	t.Run("evaluates observed games once", func(t *testing.T) {
+		d, store := setupDetectorTest(
+			&model.RatingStats{Count: 100, Average: 3, LowRating: 95},
+			&model.RatingStats{Count: 50, Average: 80, LowRating: 2},
+		)
+
+		store.On("GetActivePeriod", mock.Anything, gameID).Return(nil, nil).Once()
+		store.On("OpenPeriod", mock.Anything, mock.Anything, time.Hour).
+			Return(&model.ReviewBombPeriod{Id: uuid.New(), GameID: gameID}, nil).Once()
+
+		d.Observe(context.Background(), &model.Review{GameID: gameID})
+		d.Observe(context.Background(), &model.Review{GameID: gameID})
+
+		changed, stop := run(d, time.Hour)
+		select {
+		case got := <-changed:
+			assert.Equal(t, gameID, got)
+		case <-time.After(time.Second):
+			t.Fatal("observed game was not evaluated")
+		}
+		stop()
+
+		store.AssertExpectations(t)
+	})
+
+	t.Run("sweep closes stale periods", func(t *testing.T) {
+		d, store := setupDetectorTest(
+			&model.RatingStats{Count: 0},
+			&model.RatingStats{Count: 50, Average: 80, LowRating: 2},
+		)
+
+		periodID := uuid.New()
+		store.On("GetBombedGames", mock.Anything).Return([]uuid.UUID{gameID}, nil).Once()
+		store.On("GetBombedGames", mock.Anything).Return([]uuid.UUID(nil), nil)
+		store.On("GetActivePeriod", mock.Anything, gameID).Return(&model.ReviewBombPeriod{Id: periodID}, nil).Once()
+		store.On("ClosePeriod", mock.Anything, periodID).Return(nil).Once()
+
+		changed, stop := run(d, 10*time.Millisecond)
+		select {
+		case got := <-changed:
+			assert.Equal(t, gameID, got)
+		case <-time.After(time.Second):
+			t.Fatal("stale period was not closed")
+		}
+		stop()
+
+		store.AssertExpectations(t)
+	})
+}
+
+func TestDetector_ExcludeFromSummary(t *testing.T) {
+	cfg := testConfig
+	cfg.ExcludeFromSummary = true
+
+	assert.False(t, NewDetector(nil, testConfig).ExcludeFromSummary())
+	assert.True(t, NewDetector(nil, cfg).ExcludeFromSummary())
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"social-service/internal/metrics"
+	"social-service/internal/model"
+	"social-service/internal/producer"
+	"social-service/internal/storage"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type AppealService struct {
+	repo      *storage.ModerationRepo
+	publisher producer.AppealPublisher
+	ratings   *RatingRefresher
+}
+
+// NewAppealService republishes the rating summary of reinstated reviews'
+// games through ratings, which may be nil.
+func NewAppealService(repo *storage.ModerationRepo, publisher producer.AppealPublisher, ratings *RatingRefresher) *AppealService {
+	return &AppealService{
+		repo:      repo,
+		publisher: publisher,
+		ratings:   ratings,
+	}
+}
+
+// FileAppeal lets the author contest the removal of their review. Each review
+// can be appealed only once.
+func (s *AppealService) FileAppeal(ctx context.Context, reviewID, userID uuid.UUID, message string) (*model.Appeal, error) {
+	var appeal *model.Appeal
+
+	err := s.repo.WithinTx(ctx, func(repo *storage.ModerationRepo) error {
+		review, err := repo.GetReviewForUpdate(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		if review.UserID != userID {
+			return model.ErrNotReviewAuthor
+		}
+
+		if review.Status == model.ReviewStatusPublished {
+			return model.ErrReviewNotRemoved
+		}
+
+		removedBy, err := repo.GetLastRemovalActor(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		appeal, err = repo.CreateAppeal(ctx, &model.Appeal{
+			ReviewID:  reviewID,
+			UserID:    userID,
+			Message:   message,
+			RemovedBy: removedBy,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.AppealsFiled.Inc()
+
+	return appeal, nil
+}
+
+func (s *AppealService) ListAppeals(ctx context.Context, status string, limit, offset int32) ([]*model.Appeal, error) {
+	if limit <= 0 {
+		limit = defaultLogLimit
+	}
+
+	if limit > maxLogLimit {
+		limit = maxLogLimit
+	}
+
+	if offset < 0 {
+		offset = 0
+	}
+
+	return s.repo.ListAppeals(ctx, status, limit, offset)
+}
+
+// ResolveAppeal closes a pending appeal as upheld or reinstated. Reinstating
+// republishes the review; both the review change and the resolution are
+// written to the moderation log. The outcome, and for a reinstated review the
+// game's rating summary, are published once committed.
+func (s *AppealService) ResolveAppeal(ctx context.Context, appealID uuid.UUID, outcome string, note string, actorID uuid.UUID) (*model.Appeal, error) {
+	var (
+		resolved   *model.Appeal
+		reinstated *model.ModeratedReview
+	)
+
+	err := s.repo.WithinTx(ctx, func(repo *storage.ModerationRepo) error {
+		appeal, err := repo.GetAppealForUpdate(ctx, appealID)
+		if err != nil {
+			return err
+		}
+
+		if appeal.Status != model.AppealStatusPending {
+			return model.ErrAppealResolved
+		}
+
+		if appeal.RemovedBy != nil && *appeal.RemovedBy == actorID {
+			return model.ErrSameModerator
+		}
+
+		if outcome == model.AppealStatusReinstated {
+			reinstated, err = reinstateReview(ctx, repo, appeal, note, actorID)
+			if err != nil {
+				return err
+			}
+		}
+
+		resolved, err = repo.ResolveAppeal(ctx, appealID, outcome, actorID, note)
+		if err != nil {
+			return err
+		}
+
+		entry, err := newLogEntry(actorID, model.ModerationActionResolveAppeal, model.ModerationTargetAppeal, appealID, note, appeal, resolved)
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.AppendLog(ctx, entry)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ModerationActions.WithLabelValues(model.ModerationActionResolveAppeal).Inc()
+
+	if err := s.publisher.PublishResolution(context.WithoutCancel(ctx), resolved); err != nil {
+		log.Ctx(ctx).Error().
+			Err(err).
+			Str("appeal_id", resolved.Id.String()).
+			Msg("AppealService.ResolveAppeal: failed to publish appeal outcome")
+	}
+
+	if reinstated != nil {
+		s.ratings.Refresh(ctx, reinstated.GameID)
+	}
+
+	return resolved, nil
+}
+
+// reinstateReview republishes the appealed review. It returns nil when the
+// review was already published.
+func reinstateReview(ctx context.Context, repo *storage.ModerationRepo, appeal *model.Appeal, note string, actorID uuid.UUID) (*model.ModeratedReview, error) {
+	before, err := repo.GetReviewForUpdate(ctx, appeal.ReviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	if before.Status == model.ReviewStatusPublished {
+		return nil, nil
+	}
+
+	after, err := repo.SetReviewStatus(ctx, appeal.ReviewID, model.ReviewStatusPublished)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := newLogEntry(actorID, model.ModerationActionRestoreReview, model.ModerationTargetReview, appeal.ReviewID, note, before, after)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := repo.AppendLog(ctx, entry); err != nil {
+		return nil, err
+	}
+
+	return after, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"social-service/internal/model"
+	"social-service/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var appealColumns = []string{"id", "review_id", "user_id", "message", "status", "removed_by", "resolved_by", "resolution_note", "created_at", "resolved_at"}
+
+type fakeAppealPublisher struct {
+	resolved []*model.Appeal
+}
+
+func (p *fakeAppealPublisher) PublishResolution(ctx context.Context, appeal *model.Appeal) error {
+	p.resolved = append(p.resolved, appeal)
+	return nil
+}
+
+func TestAppealService_ResolveAppeal(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	ratings := &fakeRatingPublisher{}
+	appeals := &fakeAppealPublisher{}
+	reviews := NewReviewService(storage.NewMemoryReviewStore(), nil, nil, 50)
+	svc := NewAppealService(storage.NewModerationRepo(db), appeals, NewRatingRefresher(reviews, ratings))
+
+	appealID, reviewID, authorID, gameID := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	removedBy, actorID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	appealRow := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows(appealColumns).
+			AddRow(appealID, reviewID, authorID, "please", status, removedBy, nil, "", now, nil)
+	}
+	reviewRow := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows(moderatedReviewColumns).
+			AddRow(reviewID, authorID, gameID, 80, "text", now, now, 1, status)
+	}
+
+	t.Run("reinstating republishes the rating", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM social.review_appeals`).WithArgs(appealID).WillReturnRows(appealRow(model.AppealStatusPending))
+		mock.ExpectQuery(`FROM social.reviews WHERE id = \$1 FOR UPDATE`).WithArgs(reviewID).WillReturnRows(reviewRow(model.ReviewStatusHidden))
+		mock.ExpectQuery(`UPDATE social.reviews SET status = \$2`).
+			WithArgs(reviewID, model.ReviewStatusPublished).
+			WillReturnRows(reviewRow(model.ReviewStatusPublished))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnRows(logEntryRow(model.ModerationActionRestoreReview))
+		mock.ExpectQuery(`UPDATE social.review_appeals`).WillReturnRows(appealRow(model.AppealStatusReinstated))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnRows(logEntryRow(model.ModerationActionResolveAppeal))
+		mock.ExpectCommit()
+
+		resolved, err := svc.ResolveAppeal(context.Background(), appealID, model.AppealStatusReinstated, "fair", actorID)
+		require.NoError(t, err)
+		assert.Equal(t, model.AppealStatusReinstated, resolved.Status)
+		assert.Len(t, appeals.resolved, 1)
+		assert.Equal(t, []uuid.UUID{gameID}, ratings.games)
+	})
+
+	t.Run("upholding leaves the rating alone", func(t *testing.T) {
+		ratings.games = nil
+
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM social.review_appeals`).WithArgs(appealID).WillReturnRows(appealRow(model.AppealStatusPending))
+		mock.ExpectQuery(`UPDATE social.review_appeals`).WillReturnRows(appealRow(model.AppealStatusUpheld))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnRows(logEntryRow(model.ModerationActionResolveAppeal))
+		mock.ExpectCommit()
+
+		_, err := svc.ResolveAppeal(context.Background(), appealID, model.AppealStatusUpheld, "", actorID)
+		require.NoError(t, err)
+		assert.Empty(t, ratings.games)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
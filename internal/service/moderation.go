@@ -0,0 +1,182 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"social-service/internal/metrics"
+	"social-service/internal/model"
+	"social-service/internal/storage"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	defaultLogLimit = 50
+	maxLogLimit     = 200
+)
+
+var reviewActionStatus = map[string]string{
+	model.ModerationActionHideReview:    model.ReviewStatusHidden,
+	model.ModerationActionDeleteReview:  model.ReviewStatusDeleted,
+	model.ModerationActionRestoreReview: model.ReviewStatusPublished,
+}
+
+type ModerationService struct {
+	repo    *storage.ModerationRepo
+	ratings *RatingRefresher
+}
+
+// NewModerationService republishes the rating summaries that moderation
+// changes through ratings, which may be nil.
+func NewModerationService(repo *storage.ModerationRepo, ratings *RatingRefresher) *ModerationService {
+	return &ModerationService{
+		repo:    repo,
+		ratings: ratings,
+	}
+}
+
+// SetShadowBan toggles the shadow ban of userID. It returns nil when the ban
+// has been lifted. Banning or unbanning moves the averages of every game the
+// user reviewed, so their summaries are republished.
+func (s *ModerationService) SetShadowBan(ctx context.Context, userID uuid.UUID, banned bool, reason string, actorID uuid.UUID) (*model.ShadowBan, error) {
+	var (
+		ban     *model.ShadowBan
+		changed bool
+	)
+
+	err := s.repo.WithinTx(ctx, func(repo *storage.ModerationRepo) error {
+		before, err := repo.GetShadowBan(ctx, userID)
+		if err != nil {
+			return err
+		}
+		changed = (before != nil) != banned
+
+		action := model.ModerationActionShadowBan
+		if banned {
+			ban, err = repo.SetShadowBan(ctx, userID, reason, actorID)
+		} else {
+			action = model.ModerationActionLiftShadowBan
+			err = repo.RemoveShadowBan(ctx, userID)
+		}
+		if err != nil {
+			return err
+		}
+
+		entry, err := newLogEntry(actorID, action, model.ModerationTargetUser, userID, reason, before, ban)
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.AppendLog(ctx, entry)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if banned {
+		metrics.ModerationActions.WithLabelValues(model.ModerationActionShadowBan).Inc()
+	} else {
+		metrics.ModerationActions.WithLabelValues(model.ModerationActionLiftShadowBan).Inc()
+	}
+
+	if changed && s.ratings != nil {
+		gameIDs, err := s.repo.GetReviewedGames(ctx, userID)
+		if err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("user_id", userID.String()).Msg("ModerationService.SetShadowBan: failed to list reviewed games")
+		}
+		s.ratings.Refresh(ctx, gameIDs...)
+	}
+
+	return ban, nil
+}
+
+func (s *ModerationService) GetShadowBan(ctx context.Context, userID uuid.UUID) (*model.ShadowBan, error) {
+	return s.repo.GetShadowBan(ctx, userID)
+}
+
+// ModerateReview applies one of the review moderation actions and records it
+// in the moderation log within the same transaction. The game's rating
+// summary is republished once committed, as the review's status decides
+// whether it counts.
+func (s *ModerationService) ModerateReview(ctx context.Context, reviewID uuid.UUID, action string, reason string, actorID uuid.UUID) (*model.ModeratedReview, error) {
+	status := reviewActionStatus[action]
+
+	var after *model.ModeratedReview
+
+	err := s.repo.WithinTx(ctx, func(repo *storage.ModerationRepo) error {
+		before, err := repo.GetReviewForUpdate(ctx, reviewID)
+		if err != nil {
+			return err
+		}
+
+		if before.Status == status {
+			return model.ErrReviewStatusUnchanged
+		}
+
+		after, err = repo.SetReviewStatus(ctx, reviewID, status)
+		if err != nil {
+			return err
+		}
+
+		entry, err := newLogEntry(actorID, action, model.ModerationTargetReview, reviewID, reason, before, after)
+		if err != nil {
+			return err
+		}
+
+		_, err = repo.AppendLog(ctx, entry)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ModerationActions.WithLabelValues(action).Inc()
+
+	s.ratings.Refresh(ctx, after.GameID)
+
+	return after, nil
+}
+
+func (s *ModerationService) ListLog(ctx context.Context, filter *model.ModerationLogFilter) ([]*model.ModerationLogEntry, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultLogLimit
+	}
+
+	if filter.Limit > maxLogLimit {
+		filter.Limit = maxLogLimit
+	}
+
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	return s.repo.ListLog(ctx, filter)
+}
+
+func newLogEntry[B, A any](actorID uuid.UUID, action, targetType string, targetID uuid.UUID, reason string, before *B, after *A) (*model.ModerationLogEntry, error) {
+	entry := &model.ModerationLogEntry{
+		ActorID:    actorID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Reason:     reason,
+	}
+
+	var err error
+
+	if before != nil {
+		if entry.Before, err = json.Marshal(before); err != nil {
+			return nil, err
+		}
+	}
+
+	if after != nil {
+		if entry.After, err = json.Marshal(after); err != nil {
+			return nil, err
+		}
+	}
+
+	return entry, nil
+}
@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"social-service/internal/model"
+	"social-service/internal/storage"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	shadowBanColumns       = []string{"user_id", "reason", "banned_by", "created_at"}
+	moderatedReviewColumns = []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version", "status"}
+	logEntryColumns        = []string{"id", "actor_id", "action", "target_type", "target_id", "reason", "before", "after", "created_at"}
+)
+
+type fakeRatingPublisher struct {
+	games []uuid.UUID
+}
+
+func (p *fakeRatingPublisher) Publish(ctx context.Context, gameID uuid.UUID, summary *model.RatingSummary) error {
+	p.games = append(p.games, gameID)
+	return nil
+}
+
+func setupModerationServiceTest(t *testing.T) (*ModerationService, sqlmock.Sqlmock, *fakeRatingPublisher, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	publisher := &fakeRatingPublisher{}
+	reviews := NewReviewService(storage.NewMemoryReviewStore(), nil, nil, 50)
+	svc := NewModerationService(storage.NewModerationRepo(db), NewRatingRefresher(reviews, publisher))
+
+	return svc, mock, publisher, func() {
+		_ = db.Close()
+	}
+}
+
+func logEntryRow(action string) *sqlmock.Rows {
+	return sqlmock.NewRows(logEntryColumns).
+		AddRow(uuid.New(), uuid.New(), action, "", uuid.New(), "", nil, nil, time.Now())
+}
+
+func TestModerationService_SetShadowBan(t *testing.T) {
+	svc, mock, publisher, cleanup := setupModerationServiceTest(t)
+	defer cleanup()
+
+	userID, actorID := uuid.New(), uuid.New()
+	gameA, gameB := uuid.New(), uuid.New()
+
+	t.Run("ban", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM social.shadow_bans`).WithArgs(userID).WillReturnRows(sqlmock.NewRows(shadowBanColumns))
+		mock.ExpectQuery(`INSERT INTO social.shadow_bans`).
+			WithArgs(userID, "spam", actorID).
+			WillReturnRows(sqlmock.NewRows(shadowBanColumns).AddRow(userID, "spam", actorID, time.Now()))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).
+			WithArgs(actorID, model.ModerationActionShadowBan, model.ModerationTargetUser, userID, "spam", nil, sqlmock.AnyArg()).
+			WillReturnRows(logEntryRow(model.ModerationActionShadowBan))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`SELECT DISTINCT game_id`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"game_id"}).AddRow(gameA).AddRow(gameB))
+
+		publisher.games = nil
+		ban, err := svc.SetShadowBan(context.Background(), userID, true, "spam", actorID)
+		assert.NoError(t, err)
+		assert.NotNil(t, ban)
+		assert.Equal(t, []uuid.UUID{gameA, gameB}, publisher.games)
+	})
+
+	t.Run("unban", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM social.shadow_bans`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(shadowBanColumns).AddRow(userID, "spam", actorID, time.Now()))
+		mock.ExpectExec(`DELETE FROM social.shadow_bans`).
+			WithArgs(userID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).
+			WithArgs(actorID, model.ModerationActionLiftShadowBan, model.ModerationTargetUser, userID, "", sqlmock.AnyArg(), nil).
+			WillReturnRows(logEntryRow(model.ModerationActionLiftShadowBan))
+		mock.ExpectCommit()
+		mock.ExpectQuery(`SELECT DISTINCT game_id`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"game_id"}).AddRow(gameA))
+
+		publisher.games = nil
+		ban, err := svc.SetShadowBan(context.Background(), userID, false, "", actorID)
+		assert.NoError(t, err)
+		assert.Nil(t, ban)
+		assert.Equal(t, []uuid.UUID{gameA}, publisher.games)
+	})
+
+	t.Run("rebanning republishes nothing", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM social.shadow_bans`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows(shadowBanColumns).AddRow(userID, "spam", actorID, time.Now()))
+		mock.ExpectQuery(`INSERT INTO social.shadow_bans`).
+			WillReturnRows(sqlmock.NewRows(shadowBanColumns).AddRow(userID, "abuse", actorID, time.Now()))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnRows(logEntryRow(model.ModerationActionShadowBan))
+		mock.ExpectCommit()
+
+		publisher.games = nil
+		_, err := svc.SetShadowBan(context.Background(), userID, true, "abuse", actorID)
+		assert.NoError(t, err)
+		assert.Empty(t, publisher.games)
+	})
+
+	t.Run("log failure rolls back", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM social.shadow_bans`).WillReturnRows(sqlmock.NewRows(shadowBanColumns))
+		mock.ExpectQuery(`INSERT INTO social.shadow_bans`).
+			WillReturnRows(sqlmock.NewRows(shadowBanColumns).AddRow(userID, "spam", actorID, time.Now()))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).WillReturnError(errors.New("db fail"))
+		mock.ExpectRollback()
+
+		publisher.games = nil
+		_, err := svc.SetShadowBan(context.Background(), userID, true, "spam", actorID)
+		assert.Error(t, err)
+		assert.Empty(t, publisher.games)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerationService_ModerateReview(t *testing.T) {
+	svc, mock, publisher, cleanup := setupModerationServiceTest(t)
+	defer cleanup()
+
+	reviewID, actorID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	reviewRow := func(status string) *sqlmock.Rows {
+		return sqlmock.NewRows(moderatedReviewColumns).
+			AddRow(reviewID, uuid.New(), uuid.New(), 10, "text", now, now, 1, status)
+	}
+
+	t.Run("hide", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FROM social.reviews WHERE id = \$1 FOR UPDATE`).WithArgs(reviewID).WillReturnRows(reviewRow(model.ReviewStatusPublished))
+		mock.ExpectQuery(`UPDATE social.reviews SET status = \$2`).
+			WithArgs(reviewID, model.ReviewStatusHidden).
+			WillReturnRows(reviewRow(model.ReviewStatusHidden))
+		mock.ExpectQuery(`INSERT INTO social.moderation_log`).
+			WithArgs(actorID, model.ModerationActionHideReview, model.ModerationTargetReview, reviewID, "offensive",
+				sqlmock.AnyArg(), sqlmock.AnyArg()).
+			WillReturnRows(logEntryRow(model.ModerationActionHideReview))
+		mock.ExpectCommit()
+
+		publisher.games = nil
+		review, err := svc.ModerateReview(context.Background(), reviewID, model.ModerationActionHideReview, "offensive", actorID)
+		assert.NoError(t, err)
+		assert.Equal(t, model.ReviewStatusHidden, review.Status)
+		assert.Equal(t, []uuid.UUID{review.GameID}, publisher.games)
+	})
+
+	t.Run("status unchanged", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectQuery(`FOR UPDATE`).WillReturnRows(reviewRow(model.ReviewStatusPublished))
+		mock.ExpectRollback()
+
+		publisher.games = nil
+		_, err := svc.ModerateReview(context.Background(), reviewID, model.ModerationActionRestoreReview, "", actorID)
+		assert.ErrorIs(t, err, model.ErrReviewStatusUnchanged)
+		assert.Empty(t, publisher.games)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerationService_ListLog(t *testing.T) {
+	svc, mock, _, cleanup := setupModerationServiceTest(t)
+	defer cleanup()
+
+	t.Run("limits are normalized", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.moderation_log ORDER BY created_at DESC, id LIMIT \$1 OFFSET \$2`).
+			WithArgs(maxLogLimit, 0).
+			WillReturnRows(sqlmock.NewRows(logEntryColumns))
+
+		_, err := svc.ListLog(context.Background(), &model.ModerationLogFilter{Limit: 10000, Offset: -3})
+		assert.NoError(t, err)
+	})
+
+	t.Run("default limit", func(t *testing.T) {
+		mock.ExpectQuery(`LIMIT \$1 OFFSET \$2`).
+			WithArgs(defaultLogLimit, 0).
+			WillReturnRows(sqlmock.NewRows(logEntryColumns))
+
+		_, err := svc.ListLog(context.Background(), &model.ModerationLogFilter{})
+		assert.NoError(t, err)
+	})
+}
+
+func TestNewLogEntry(t *testing.T) {
+	before := &model.ShadowBan{Reason: "spam"}
+
+	entry, err := newLogEntry[model.ShadowBan, model.ShadowBan](uuid.New(), model.ModerationActionLiftShadowBan, model.ModerationTargetUser, uuid.New(), "", before, nil)
+	require.NoError(t, err)
+
+	var snapshot map[string]any
+	require.NoError(t, json.Unmarshal(entry.Before, &snapshot))
+	assert.Equal(t, "spam", snapshot["reason"])
+	assert.Nil(t, entry.After)
+}
@@ -0,0 +1,47 @@
+package service
+
+import (
+	"context"
+	"social-service/internal/producer"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// RatingRefresher republishes the rating summary of games whose average a
+// moderation change has moved, so downstream averages do not wait for the
+// next review.
+type RatingRefresher struct {
+	reviews  *ReviewService
+	producer producer.RatingPublisher
+}
+
+func NewRatingRefresher(reviews *ReviewService, producer producer.RatingPublisher) *RatingRefresher {
+	return &RatingRefresher{
+		reviews:  reviews,
+		producer: producer,
+	}
+}
+
+// Refresh recomputes and publishes the summary of each game. The change is
+// already committed by then, so failures are logged rather than returned.
+// A nil RatingRefresher publishes nothing.
+func (r *RatingRefresher) Refresh(ctx context.Context, gameIDs ...uuid.UUID) {
+	if r == nil {
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+
+	for _, gameID := range gameIDs {
+		summary, err := r.reviews.GetRatingSummary(ctx, gameID)
+		if err != nil {
+			log.Ctx(ctx).Warn().Err(err).Str("game_id", gameID.String()).Msg("RatingRefresher: failed to compute rating summary")
+			continue
+		}
+
+		if err := r.producer.Publish(ctx, gameID, summary); err != nil {
+			log.Ctx(ctx).Error().Err(err).Str("game_id", gameID.String()).Msg("RatingRefresher: failed to publish rating update to broker")
+		}
+	}
+}
@@ -2,50 +2,107 @@ package service
 
 import (
 	"context"
+	"social-service/internal/metrics"
 	"social-service/internal/model"
 	"social-service/internal/storage"
 
+	"github.com/google/uuid"
 	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
 )
 
+// defaultPageSize is the page size of listings that do not set a limit.
+const defaultPageSize = 20
+
+type BombDetector interface {
+	Observe(ctx context.Context, review *model.Review)
+	ExcludeFromSummary() bool
+}
+
+// FeedPublisher broadcasts newly created reviews to live subscribers.
+type FeedPublisher interface {
+	Publish(ctx context.Context, review *model.Review)
+}
+
 type ReviewService struct {
-	repo *storage.ReviewRepo
+	repo     storage.ReviewStore
+	detector BombDetector
+	feed     FeedPublisher
+
+	maxPageSize int32
 }
 
-func NewReviewService(repo *storage.ReviewRepo) *ReviewService {
+// NewReviewService caps every listing at maxPageSize reviews.
+func NewReviewService(repo storage.ReviewStore, detector BombDetector, feed FeedPublisher, maxPageSize int) *ReviewService {
 	return &ReviewService{
-		repo: repo,
+		repo:        repo,
+		detector:    detector,
+		feed:        feed,
+		maxPageSize: int32(maxPageSize),
 	}
 }
 
 func (s *ReviewService) CreateReview(ctx context.Context, req *socialpb.CreateReviewRequest) (*model.Review, error) {
-	return s.repo.CreateReview(ctx, req)
+	review, err := s.repo.CreateReview(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	metrics.ReviewsCreated.Inc()
+
+	if s.detector != nil {
+		s.detector.Observe(ctx, review)
+	}
+
+	if s.feed != nil {
+		s.feed.Publish(ctx, review)
+	}
+
+	return review, nil
+}
+
+func (s *ReviewService) GetReview(ctx context.Context, reviewID, viewerID uuid.UUID) (*model.Review, error) {
+	return s.repo.GetReview(ctx, reviewID, viewerID)
 }
 
-func (s *ReviewService) GetReviewsByUser(ctx context.Context, req *socialpb.GetUserReviewsRequest) ([]*model.Review, error) {
-	if req.Limit < 0 {
-		req.Limit = 0
+// UpdateReview edits the author's review provided nobody changed it since
+// expectedVersion.
+func (s *ReviewService) UpdateReview(ctx context.Context, reviewID, userID uuid.UUID, rating int32, text string, expectedVersion int) (*model.Review, error) {
+	review, err := s.repo.UpdateReview(ctx, reviewID, userID, rating, text, expectedVersion)
+	if err != nil {
+		return nil, err
 	}
 
+	metrics.ReviewsUpdated.Inc()
+
+	return review, nil
+}
+
+func (s *ReviewService) GetRatingSummary(ctx context.Context, gameID uuid.UUID) (*model.RatingSummary, error) {
+	excludeBombs := s.detector != nil && s.detector.ExcludeFromSummary()
+
+	return s.repo.GetRatingSummary(ctx, gameID, excludeBombs)
+}
+
+// GetReviewsByUser lists the target user's reviews as seen by viewerID, which
+// is empty for anonymous callers. Shadow-banned users only see their own.
+func (s *ReviewService) GetReviewsByUser(ctx context.Context, req *socialpb.GetUserReviewsRequest, viewerID string) ([]*model.Review, error) {
+	req.Limit = s.pageLimit(req.Limit)
+
 	if req.Offset < 0 {
 		req.Offset = 0
 	}
 
-	return s.repo.GetReviewsByUser(ctx, req)
+	return s.repo.GetReviewsByUser(ctx, req, viewerID != "" && viewerID == req.UserId)
 }
 
 func (s *ReviewService) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest) ([]*model.Review, error) {
-	if req.Limit < 0 {
-		req.Limit = 0
-	}
+	req.Limit = s.pageLimit(req.Limit)
 
 	return s.repo.GetFeed(ctx, req)
 }
 
 func (s *ReviewService) GetReviewsByGame(ctx context.Context, req *socialpb.GetGameReviewsRequest) ([]*model.Review, error) {
-	if req.Limit < 0 {
-		req.Limit = 0
-	}
+	req.Limit = s.pageLimit(req.Limit)
 
 	if req.Offset < 0 {
 		req.Offset = 0
@@ -53,3 +110,13 @@ func (s *ReviewService) GetReviewsByGame(ctx context.Context, req *socialpb.GetG
 
 	return s.repo.GetReviewsByGame(ctx, req)
 }
+
+// pageLimit turns a requested limit into the page size to query: unset or
+// negative limits get the default, and none exceeds maxPageSize.
+func (s *ReviewService) pageLimit(limit int32) int32 {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	return min(limit, s.maxPageSize)
+}
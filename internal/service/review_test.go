@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"social-service/internal/model"
 	"social-service/internal/storage"
 	"testing"
 	"time"
@@ -18,7 +19,7 @@ func setupServiceTest(t *testing.T) (*ReviewService, sqlmock.Sqlmock, func()) {
 	require.NoError(t, err)
 
 	repo := storage.NewReviewRepo(db)
-	svc := NewReviewService(repo)
+	svc := NewReviewService(repo, nil, nil, 50)
 
 	return svc, mock, func() {
 		_ = db.Close()
@@ -37,8 +38,8 @@ func TestReviewService_CreateReview(t *testing.T) {
 	}
 
 	t.Run("success", func(t *testing.T) {
-		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}).
-			AddRow(uuid.New().String(), req.UserId, req.GameId, req.Rating, req.Text, time.Now(), time.Now())
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), req.UserId, req.GameId, req.Rating, req.Text, time.Now(), time.Now(), 1)
 
 		mock.ExpectQuery(`INSERT INTO social.reviews`).WillReturnRows(rows)
 
@@ -48,6 +49,50 @@ func TestReviewService_CreateReview(t *testing.T) {
 	})
 }
 
+type fakeDetector struct {
+	observed []*model.Review
+	exclude  bool
+}
+
+func (d *fakeDetector) Observe(ctx context.Context, review *model.Review) {
+	d.observed = append(d.observed, review)
+}
+
+func (d *fakeDetector) ExcludeFromSummary() bool {
+	return d.exclude
+}
+
+func TestReviewService_ReviewBombDetector(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	detector := &fakeDetector{exclude: true}
+	svc := NewReviewService(storage.NewReviewRepo(db), detector, nil, 50)
+
+	gameID := uuid.New()
+
+	t.Run("created review is observed", func(t *testing.T) {
+		rows := sqlmock.NewRows([]string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}).
+			AddRow(uuid.New().String(), uuid.New().String(), gameID.String(), 0, "", time.Now(), time.Now(), 1)
+		mock.ExpectQuery(`INSERT INTO social.reviews`).WillReturnRows(rows)
+
+		res, err := svc.CreateReview(context.Background(), &socialpb.CreateReviewRequest{GameId: gameID.String()})
+		assert.NoError(t, err)
+		assert.Equal(t, []*model.Review{res}, detector.observed)
+	})
+
+	t.Run("summary excludes bombs", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT COUNT`).
+			WithArgs(gameID, true).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "avg", "exists"}).AddRow(1, 0.0, true))
+
+		summary, err := svc.GetRatingSummary(context.Background(), gameID)
+		assert.NoError(t, err)
+		assert.True(t, summary.ReviewBomb)
+	})
+}
+
 func TestReviewService_GetReviewsByUser(t *testing.T) {
 	svc, mock, cleanup := setupServiceTest(t)
 	defer cleanup()
@@ -62,22 +107,22 @@ func TestReviewService_GetReviewsByUser(t *testing.T) {
 		}
 
 		mock.ExpectQuery(`LIMIT \$2 OFFSET \$3`).
-			WithArgs(userID, 0, 0).
+			WithArgs(userID, defaultPageSize, 0, false).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
-		_, err := svc.GetReviewsByUser(context.Background(), req)
+		_, err := svc.GetReviewsByUser(context.Background(), req, "")
 		assert.NoError(t, err)
-		assert.Equal(t, int32(0), req.Limit)
+		assert.Equal(t, int32(defaultPageSize), req.Limit)
 		assert.Equal(t, int32(0), req.Offset)
 	})
 
 	t.Run("positive values remains", func(t *testing.T) {
 		req := &socialpb.GetUserReviewsRequest{UserId: userID, Limit: 10, Offset: 20}
 		mock.ExpectQuery(`LIMIT \$2 OFFSET \$3`).
-			WithArgs(userID, 10, 20).
+			WithArgs(userID, 10, 20, false).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
-		_, err := svc.GetReviewsByUser(context.Background(), req)
+		_, err := svc.GetReviewsByUser(context.Background(), req, "")
 		assert.NoError(t, err)
 	})
 }
@@ -90,12 +135,23 @@ func TestReviewService_GetFeed(t *testing.T) {
 		req := &socialpb.GetFeedRequest{Limit: -1}
 
 		mock.ExpectQuery(`LIMIT \$1`).
-			WithArgs(0).
+			WithArgs(defaultPageSize).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		_, err := svc.GetFeed(context.Background(), req)
+		assert.NoError(t, err)
+		assert.Equal(t, int32(defaultPageSize), req.Limit)
+	})
+
+	t.Run("limit above the page size is capped", func(t *testing.T) {
+		req := &socialpb.GetFeedRequest{Limit: 500}
+
+		mock.ExpectQuery(`LIMIT \$1`).
+			WithArgs(50).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
 		_, err := svc.GetFeed(context.Background(), req)
 		assert.NoError(t, err)
-		assert.Equal(t, int32(0), req.Limit)
 	})
 }
 
@@ -112,8 +168,8 @@ func TestReviewService_GetReviewsByGame(t *testing.T) {
 			Offset: -1,
 		}
 
-		mock.ExpectQuery(`WHERE game_id = \$1`).
-			WithArgs(gameID, nil, 0).
+		mock.ExpectQuery(`WHERE r.game_id = \$1 AND r.status = 'published' AND NOT EXISTS`).
+			WithArgs(gameID, defaultPageSize, 0).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
 		_, err := svc.GetReviewsByGame(context.Background(), req)
@@ -123,7 +179,7 @@ func TestReviewService_GetReviewsByGame(t *testing.T) {
 	t.Run("success with positive values", func(t *testing.T) {
 		req := &socialpb.GetGameReviewsRequest{GameId: gameID, Limit: 5, Offset: 10}
 
-		mock.ExpectQuery(`WHERE game_id = \$1`).
+		mock.ExpectQuery(`WHERE r.game_id = \$1 AND r.status = 'published' AND NOT EXISTS`).
 			WithArgs(gameID, 5, 10).
 			WillReturnRows(sqlmock.NewRows([]string{"id"}))
 
@@ -131,3 +187,58 @@ func TestReviewService_GetReviewsByGame(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestReviewService_PageSize(t *testing.T) {
+	svc := NewReviewService(storage.NewMemoryReviewStore(), nil, nil, 3)
+	ctx := context.Background()
+	gameID := uuid.NewString()
+
+	for range 5 {
+		_, err := svc.CreateReview(ctx, &socialpb.CreateReviewRequest{UserId: uuid.NewString(), GameId: gameID, Rating: 50})
+		require.NoError(t, err)
+	}
+
+	for _, limit := range []int32{0, -1, 10} {
+		reviews, err := svc.GetReviewsByGame(ctx, &socialpb.GetGameReviewsRequest{GameId: gameID, Limit: limit})
+		require.NoError(t, err)
+		assert.Len(t, reviews, 3, "limit %d", limit)
+	}
+}
+
+func TestReviewService_MemoryStore(t *testing.T) {
+	svc := NewReviewService(storage.NewMemoryReviewStore(), nil, nil, 50)
+	ctx := context.Background()
+
+	userID, gameID := uuid.New().String(), uuid.New()
+
+	_, err := svc.CreateReview(ctx, &socialpb.CreateReviewRequest{UserId: userID, GameId: gameID.String(), Rating: 70})
+	require.NoError(t, err)
+	_, err = svc.CreateReview(ctx, &socialpb.CreateReviewRequest{UserId: uuid.New().String(), GameId: gameID.String(), Rating: 30})
+	require.NoError(t, err)
+
+	summary, err := svc.GetRatingSummary(ctx, gameID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, summary.Count)
+	assert.Equal(t, 50.0, summary.Average)
+
+	reviews, err := svc.GetReviewsByUser(ctx, &socialpb.GetUserReviewsRequest{UserId: userID, Limit: 10, Offset: -1}, "")
+	require.NoError(t, err)
+	assert.Len(t, reviews, 1)
+}
+
+type fakeFeed struct {
+	published []*model.Review
+}
+
+func (f *fakeFeed) Publish(ctx context.Context, review *model.Review) {
+	f.published = append(f.published, review)
+}
+
+func TestReviewService_PublishesToFeed(t *testing.T) {
+	feed := &fakeFeed{}
+	svc := NewReviewService(storage.NewMemoryReviewStore(), nil, feed, 50)
+
+	review, err := svc.CreateReview(context.Background(), &socialpb.CreateReviewRequest{UserId: uuid.NewString(), GameId: uuid.NewString(), Rating: 60})
+	require.NoError(t, err)
+	assert.Equal(t, []*model.Review{review}, feed.published)
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"social-service/internal/model"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+const appealColumns = `
+	id, review_id, user_id, message, status, removed_by, resolved_by,
+	COALESCE(resolution_note, ''), created_at, resolved_at
+`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanAppeal(row rowScanner) (*model.Appeal, error) {
+	appeal := &model.Appeal{}
+
+	err := row.Scan(
+		&appeal.Id, &appeal.ReviewID,
+		&appeal.UserID, &appeal.Message,
+		&appeal.Status, &appeal.RemovedBy,
+		&appeal.ResolvedBy, &appeal.ResolutionNote,
+		&appeal.CreatedAt, &appeal.ResolvedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return appeal, nil
+}
+
+// GetLastRemovalActor returns the moderator who last hid or deleted the
+// review, or nil if the removal predates the moderation log.
+func (r *ModerationRepo) GetLastRemovalActor(ctx context.Context, reviewID uuid.UUID) (*uuid.UUID, error) {
+	var actorID uuid.UUID
+
+	query := `
+		SELECT actor_id
+		FROM social.moderation_log
+		WHERE target_type = $1 AND target_id = $2 AND action IN ($3, $4)
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	err := r.q.QueryRowContext(ctx, query,
+		model.ModerationTargetReview, reviewID,
+		model.ModerationActionHideReview, model.ModerationActionDeleteReview,
+	).Scan(&actorID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &actorID, nil
+}
+
+func (r *ModerationRepo) CreateAppeal(ctx context.Context, appeal *model.Appeal) (*model.Appeal, error) {
+	query := `
+		INSERT INTO social.review_appeals (review_id, user_id, message, removed_by)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + appealColumns
+
+	created, err := scanAppeal(r.q.QueryRowContext(ctx, query,
+		appeal.ReviewID, appeal.UserID, appeal.Message, appeal.RemovedBy,
+	))
+	if err != nil {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			if pqErr.Code == "23505" {
+				return nil, model.ErrAppealExists
+			}
+		}
+
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// GetAppealForUpdate locks the appeal row until the transaction ends.
+func (r *ModerationRepo) GetAppealForUpdate(ctx context.Context, appealID uuid.UUID) (*model.Appeal, error) {
+	query := `
+		SELECT ` + appealColumns + `
+		FROM social.review_appeals
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	appeal, err := scanAppeal(r.q.QueryRowContext(ctx, query, appealID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrAppealNotFound
+		}
+
+		return nil, err
+	}
+
+	return appeal, nil
+}
+
+func (r *ModerationRepo) ResolveAppeal(ctx context.Context, appealID uuid.UUID, status string, resolvedBy uuid.UUID, note string) (*model.Appeal, error) {
+	query := `
+		UPDATE social.review_appeals
+		SET status = $2, resolved_by = $3, resolution_note = $4, resolved_at = NOW()
+		WHERE id = $1
+		RETURNING ` + appealColumns
+
+	appeal, err := scanAppeal(r.q.QueryRowContext(ctx, query, appealID, status, resolvedBy, note))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrAppealNotFound
+		}
+
+		return nil, err
+	}
+
+	return appeal, nil
+}
+
+// ListAppeals returns appeals oldest first, so moderators work the queue in
+// order. An empty status lists appeals in any state.
+func (r *ModerationRepo) ListAppeals(ctx context.Context, status string, limit, offset int32) ([]*model.Appeal, error) {
+	appeals := make([]*model.Appeal, 0, limit)
+
+	query := `
+		SELECT ` + appealColumns + `
+		FROM social.review_appeals
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at, id
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.q.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error().Err(err).Msg("moderation_repo: failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		appeal, err := scanAppeal(rows)
+		if err != nil {
+			return nil, err
+		}
+
+		appeals = append(appeals, appeal)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return appeals, nil
+}
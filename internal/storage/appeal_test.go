@@ -0,0 +1,129 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+var appealTestColumns = []string{
+	"id", "review_id", "user_id", "message", "status", "removed_by", "resolved_by",
+	"resolution_note", "created_at", "resolved_at",
+}
+
+func TestModerationRepo_GetLastRemovalActor(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	reviewID, actorID := uuid.New(), uuid.New()
+
+	t.Run("found", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT actor_id FROM social.moderation_log`).
+			WithArgs("review", reviewID, "hide_review", "delete_review").
+			WillReturnRows(sqlmock.NewRows([]string{"actor_id"}).AddRow(actorID))
+
+		actor, err := repo.GetLastRemovalActor(ctx, reviewID)
+		assert.NoError(t, err)
+		assert.Equal(t, actorID, *actor)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT actor_id`).WillReturnError(sql.ErrNoRows)
+		actor, err := repo.GetLastRemovalActor(ctx, reviewID)
+		assert.NoError(t, err)
+		assert.Nil(t, actor)
+	})
+}
+
+func TestModerationRepo_CreateAppeal(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	appeal := &model.Appeal{ReviewID: uuid.New(), UserID: uuid.New(), Message: "please"}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO social.review_appeals`).
+			WithArgs(appeal.ReviewID, appeal.UserID, "please", nil).
+			WillReturnRows(sqlmock.NewRows(appealTestColumns).
+				AddRow(uuid.New(), appeal.ReviewID, appeal.UserID, "please", "pending", nil, nil, "", time.Now(), nil))
+
+		created, err := repo.CreateAppeal(ctx, appeal)
+		assert.NoError(t, err)
+		assert.Equal(t, "pending", created.Status)
+		assert.Nil(t, created.RemovedBy)
+		assert.Nil(t, created.ResolvedAt)
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO`).WillReturnError(&pq.Error{Code: "23505"})
+		_, err := repo.CreateAppeal(ctx, appeal)
+		assert.ErrorIs(t, err, model.ErrAppealExists)
+	})
+
+	t.Run("generic error", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO`).WillReturnError(errors.New("db fail"))
+		_, err := repo.CreateAppeal(ctx, appeal)
+		assert.Error(t, err)
+	})
+}
+
+func TestModerationRepo_ResolveAppeal(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	appealID, actorID := uuid.New(), uuid.New()
+	now := time.Now()
+
+	t.Run("get for update missing", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.review_appeals WHERE id = \$1 FOR UPDATE`).WillReturnError(sql.ErrNoRows)
+		_, err := repo.GetAppealForUpdate(ctx, appealID)
+		assert.ErrorIs(t, err, model.ErrAppealNotFound)
+	})
+
+	t.Run("resolve", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE social.review_appeals SET status = \$2, resolved_by = \$3`).
+			WithArgs(appealID, "upheld", actorID, "no").
+			WillReturnRows(sqlmock.NewRows(appealTestColumns).
+				AddRow(appealID, uuid.New(), uuid.New(), "please", "upheld", uuid.New(), actorID, "no", now, now))
+
+		appeal, err := repo.ResolveAppeal(ctx, appealID, "upheld", actorID, "no")
+		assert.NoError(t, err)
+		assert.Equal(t, actorID, *appeal.ResolvedBy)
+		assert.NotNil(t, appeal.ResolvedAt)
+	})
+}
+
+func TestModerationRepo_ListAppeals(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.review_appeals WHERE \$1 = '' OR status = \$1 ORDER BY created_at, id LIMIT \$2 OFFSET \$3`).
+			WithArgs("", 10, 0).
+			WillReturnRows(sqlmock.NewRows(appealTestColumns).
+				AddRow(uuid.New(), uuid.New(), uuid.New(), "m", "pending", nil, nil, "", time.Now(), nil))
+
+		appeals, err := repo.ListAppeals(ctx, "", 10, 0)
+		assert.NoError(t, err)
+		assert.Len(t, appeals, 1)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db fail"))
+		_, err := repo.ListAppeals(ctx, "", 10, 0)
+		assert.Error(t, err)
+	})
+}
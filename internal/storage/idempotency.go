@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"social-service/internal/idempotency"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// IdempotencyRepo keeps idempotency records in Postgres so that a retry is
+// recognised whichever replica it reaches.
+type IdempotencyRepo struct {
+	db *sql.DB
+}
+
+func NewIdempotencyRepo(db *sql.DB) *IdempotencyRepo {
+	return &IdempotencyRepo{db: db}
+}
+
+func (r *IdempotencyRepo) Reserve(ctx context.Context, key string, hash []byte, ttl, lease time.Duration) (*idempotency.Record, error) {
+	// The insert only takes over an existing key once it has expired or its
+	// request was abandoned; otherwise the live record is read back. A key
+	// released between the two statements is simply claimed on the next pass.
+	claim := `
+		INSERT INTO social.idempotency_keys (key, request_hash, expires_at)
+		VALUES ($1, $2, NOW() + make_interval(secs => $3))
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			response_type = NULL,
+			response = NULL,
+			response_header = NULL,
+			created_at = NOW(),
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= NOW()
+			OR (idempotency_keys.response IS NULL AND idempotency_keys.created_at <= NOW() - make_interval(secs => $4))
+		RETURNING key
+	`
+
+	existing := `
+		SELECT request_hash, response_type, response, response_header
+		FROM social.idempotency_keys
+		WHERE key = $1 AND expires_at > NOW()
+	`
+
+	for attempt := 0; attempt < 2; attempt++ {
+		var claimed string
+		err := r.db.QueryRowContext(ctx, claim, key, hash, ttl.Seconds(), lease.Seconds()).Scan(&claimed)
+		if err == nil {
+			return nil, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		var (
+			record       idempotency.Record
+			responseType sql.NullString
+			response     []byte
+			header       []byte
+		)
+		err = r.db.QueryRowContext(ctx, existing, key).Scan(&record.RequestHash, &responseType, &response, &header)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if responseType.Valid {
+			record.Response = &anypb.Any{TypeUrl: responseType.String, Value: response}
+		}
+
+		if header != nil {
+			if err := json.Unmarshal(header, &record.Header); err != nil {
+				return nil, fmt.Errorf("decode response header: %w", err)
+			}
+		}
+
+		return &record, nil
+	}
+
+	return nil, errors.New("idempotency key changed concurrently")
+}
+
+func (r *IdempotencyRepo) Complete(ctx context.Context, key string, response *anypb.Any, header metadata.MD) error {
+	query := `
+		UPDATE social.idempotency_keys
+		SET response_type = $2, response = $3, response_header = $4
+		WHERE key = $1
+	`
+
+	var encoded sql.NullString
+	if len(header) > 0 {
+		data, err := json.Marshal(header)
+		if err != nil {
+			return err
+		}
+		encoded = sql.NullString{String: string(data), Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, query, key, response.TypeUrl, response.Value, encoded)
+
+	return err
+}
+
+func (r *IdempotencyRepo) Release(ctx context.Context, key string) error {
+	query := `DELETE FROM social.idempotency_keys WHERE key = $1 AND response IS NULL`
+
+	_, err := r.db.ExecContext(ctx, query, key)
+
+	return err
+}
+
+// Purge deletes expired records and reports how many were removed.
+func (r *IdempotencyRepo) Purge(ctx context.Context) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM social.idempotency_keys WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// RunPurge calls Purge every interval until ctx is cancelled.
+func (r *IdempotencyRepo) RunPurge(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := r.Purge(ctx)
+			if err != nil {
+				log.Error().Err(err).Msg("idempotency_repo: failed to purge expired keys")
+				continue
+			}
+			if purged > 0 {
+				log.Debug().Int64("purged", purged).Msg("idempotency_repo: purged expired keys")
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func setupIdempotencyRepoTest(t *testing.T) (*IdempotencyRepo, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	return NewIdempotencyRepo(db), mock, func() {
+		_ = db.Close()
+	}
+}
+
+func TestIdempotencyRepo_Reserve(t *testing.T) {
+	repo, mock, cleanup := setupIdempotencyRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	hash := []byte("hash")
+
+	t.Run("claims a free key", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO social.idempotency_keys`).
+			WithArgs("k", hash, float64(3600), float64(60)).
+			WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("k"))
+
+		existing, err := repo.Reserve(ctx, "k", hash, time.Hour, time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+
+	t.Run("returns the completed record", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO social.idempotency_keys`).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT request_hash, response_type, response, response_header`).
+			WithArgs("k").
+			WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response_type", "response", "response_header"}).
+				AddRow(hash, "type", []byte("v"), []byte(`{"etag":["\"2\""]}`)))
+
+		existing, err := repo.Reserve(ctx, "k", hash, time.Hour, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Equal(t, hash, existing.RequestHash)
+		assert.Equal(t, &anypb.Any{TypeUrl: "type", Value: []byte("v")}, existing.Response)
+		assert.Equal(t, []string{`"2"`}, existing.Header.Get("etag"))
+	})
+
+	t.Run("returns a pending record", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO social.idempotency_keys`).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT request_hash`).
+			WillReturnRows(sqlmock.NewRows([]string{"request_hash", "response_type", "response", "response_header"}).
+				AddRow(hash, nil, nil, nil))
+
+		existing, err := repo.Reserve(ctx, "k", hash, time.Hour, time.Minute)
+		require.NoError(t, err)
+		require.NotNil(t, existing)
+		assert.Nil(t, existing.Response)
+		assert.Nil(t, existing.Header)
+	})
+
+	t.Run("claims a key released in between", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO social.idempotency_keys`).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT request_hash`).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`INSERT INTO social.idempotency_keys`).
+			WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("k"))
+
+		existing, err := repo.Reserve(ctx, "k", hash, time.Hour, time.Minute)
+		require.NoError(t, err)
+		assert.Nil(t, existing)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIdempotencyRepo_CompleteReleasePurge(t *testing.T) {
+	repo, mock, cleanup := setupIdempotencyRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	mock.ExpectExec(`UPDATE social.idempotency_keys`).
+		WithArgs("k", "type", []byte("v"), `{"etag":["\"2\""]}`).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Complete(ctx, "k", &anypb.Any{TypeUrl: "type", Value: []byte("v")}, metadata.Pairs("etag", `"2"`)))
+
+	mock.ExpectExec(`UPDATE social.idempotency_keys`).
+		WithArgs("k", "type", []byte("v"), nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Complete(ctx, "k", &anypb.Any{TypeUrl: "type", Value: []byte("v")}, nil))
+
+	mock.ExpectExec(`DELETE FROM social.idempotency_keys WHERE key = \$1 AND response IS NULL`).
+		WithArgs("k").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.Release(ctx, "k"))
+
+	mock.ExpectExec(`DELETE FROM social.idempotency_keys WHERE expires_at <= NOW\(\)`).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	purged, err := repo.Purge(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), purged)
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
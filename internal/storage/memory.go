@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"social-service/internal/model"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"github.com/viktoralyoshin/utils/pkg/errs"
+)
+
+type userGame struct {
+	userID uuid.UUID
+	gameID uuid.UUID
+}
+
+type memoryReview struct {
+	review *model.Review
+	seq    int
+}
+
+// MemoryReviewStore keeps reviews in memory for tests and local runs. It has
+// no moderation state: every review is published, no user is shadow-banned
+// and no review-bomb period is ever open.
+type MemoryReviewStore struct {
+	mu      sync.RWMutex
+	reviews []memoryReview
+	byPair  map[userGame]struct{}
+	seq     int
+	now     func() time.Time
+}
+
+func NewMemoryReviewStore() *MemoryReviewStore {
+	return &MemoryReviewStore{
+		byPair: make(map[userGame]struct{}),
+		now:    time.Now,
+	}
+}
+
+func (s *MemoryReviewStore) CreateReview(ctx context.Context, req *socialpb.CreateReviewRequest) (*model.Review, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	gameID, err := uuid.Parse(req.GameId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid game_id: %w", err)
+	}
+
+	if req.Rating < model.MinRating || req.Rating > model.MaxRating {
+		return nil, fmt.Errorf("rating %d is out of range", req.Rating)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pair := userGame{userID: userID, gameID: gameID}
+	if _, ok := s.byPair[pair]; ok {
+		return nil, errs.ErrReviewExists
+	}
+
+	now := s.now().UTC()
+	review := &model.Review{
+		Id:        uuid.New(),
+		UserID:    userID,
+		GameID:    gameID,
+		Rating:    int(req.Rating),
+		Text:      req.Text,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Version:   1,
+	}
+
+	s.seq++
+	s.reviews = append(s.reviews, memoryReview{review: review, seq: s.seq})
+	s.byPair[pair] = struct{}{}
+
+	created := *review
+	return &created, nil
+}
+
+func (s *MemoryReviewStore) GetReview(ctx context.Context, reviewID, viewerID uuid.UUID) (*model.Review, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	r := s.find(reviewID)
+	if r == nil {
+		return nil, model.ErrReviewNotFound
+	}
+
+	review := *r
+	return &review, nil
+}
+
+func (s *MemoryReviewStore) UpdateReview(ctx context.Context, reviewID, userID uuid.UUID, rating int32, text string, expectedVersion int) (*model.Review, error) {
+	if rating < model.MinRating || rating > model.MaxRating {
+		return nil, fmt.Errorf("rating %d is out of range", rating)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	r := s.find(reviewID)
+	if r == nil {
+		return nil, model.ErrReviewNotFound
+	}
+
+	if r.Version != expectedVersion || r.UserID != userID {
+		return nil, updateRefusal(r.UserID, userID, model.ReviewStatusPublished)
+	}
+
+	r.Rating = int(rating)
+	r.Text = text
+	r.UpdatedAt = s.now().UTC()
+	r.Version++
+
+	updated := *r
+	return &updated, nil
+}
+
+// find returns the stored review; the caller holds the lock.
+func (s *MemoryReviewStore) find(reviewID uuid.UUID) *model.Review {
+	for _, r := range s.reviews {
+		if r.review.Id == reviewID {
+			return r.review
+		}
+	}
+
+	return nil
+}
+
+func (s *MemoryReviewStore) GetReviewsByUser(ctx context.Context, req *socialpb.GetUserReviewsRequest, includeShadowBanned bool) ([]*model.Review, error) {
+	userID, err := uuid.Parse(req.UserId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user_id: %w", err)
+	}
+
+	return s.list(func(r *model.Review) bool { return r.UserID == userID }, int(req.Limit), int(req.Offset)), nil
+}
+
+func (s *MemoryReviewStore) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest) ([]*model.Review, error) {
+	return s.list(func(*model.Review) bool { return true }, int(req.Limit), 0), nil
+}
+
+func (s *MemoryReviewStore) GetReviewsByGame(ctx context.Context, req *socialpb.GetGameReviewsRequest) ([]*model.Review, error) {
+	gameID, err := uuid.Parse(req.GameId)
+	if err != nil {
+		return nil, fmt.Errorf("invalid game_id: %w", err)
+	}
+
+	return s.list(func(r *model.Review) bool { return r.GameID == gameID }, int(req.Limit), int(req.Offset)), nil
+}
+
+func (s *MemoryReviewStore) GetRatingSummary(ctx context.Context, gameID uuid.UUID, excludeBombs bool) (*model.RatingSummary, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	summary := &model.RatingSummary{GameID: gameID}
+
+	total := 0
+	for _, r := range s.reviews {
+		if r.review.GameID == gameID {
+			summary.Count++
+			total += r.review.Rating
+		}
+	}
+
+	if summary.Count > 0 {
+		summary.Average = float64(total) / float64(summary.Count)
+	}
+
+	return summary, nil
+}
+
+// list returns matching reviews newest first. Reviews created in the same
+// instant keep insertion order reversed, as a stable tie-break.
+func (s *MemoryReviewStore) list(match func(*model.Review) bool, limit, offset int) []*model.Review {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	matched := make([]memoryReview, 0, len(s.reviews))
+	for _, r := range s.reviews {
+		if match(r.review) {
+			matched = append(matched, r)
+		}
+	}
+
+	slices.SortFunc(matched, func(a, b memoryReview) int {
+		if c := b.review.CreatedAt.Compare(a.review.CreatedAt); c != 0 {
+			return c
+		}
+		return b.seq - a.seq
+	})
+
+	if offset > len(matched) {
+		offset = len(matched)
+	}
+	matched = matched[offset:]
+
+	if limit < len(matched) {
+		matched = matched[:limit]
+	}
+
+	reviews := make([]*model.Review, 0, len(matched))
+	for _, r := range matched {
+		review := *r.review
+		reviews = append(reviews, &review)
+	}
+
+	return reviews
+}
@@ -0,0 +1,305 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"social-service/internal/model"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+type ModerationRepo struct {
+	db *sql.DB
+	q  querier
+}
+
+func NewModerationRepo(db *sql.DB) *ModerationRepo {
+	return &ModerationRepo{
+		db: db,
+		q:  db,
+	}
+}
+
+// WithinTx runs fn against a repo bound to a single transaction, so that a
+// moderation action and its audit record are committed together.
+func (r *ModerationRepo) WithinTx(ctx context.Context, fn func(repo *ModerationRepo) error) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(&ModerationRepo{db: r.db, q: tx}); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			log.Error().Err(rbErr).Msg("moderation_repo: failed to rollback transaction")
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (r *ModerationRepo) SetShadowBan(ctx context.Context, userID uuid.UUID, reason string, bannedBy uuid.UUID) (*model.ShadowBan, error) {
+	ban := &model.ShadowBan{}
+
+	query := `
+		INSERT INTO social.shadow_bans (user_id, reason, banned_by)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET reason = EXCLUDED.reason, banned_by = EXCLUDED.banned_by
+		RETURNING user_id, COALESCE(reason, ''), banned_by, created_at
+	`
+
+	err := r.q.QueryRowContext(ctx, query, userID, reason, bannedBy).Scan(
+		&ban.UserID, &ban.Reason, &ban.BannedBy, &ban.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+func (r *ModerationRepo) RemoveShadowBan(ctx context.Context, userID uuid.UUID) error {
+	query := `DELETE FROM social.shadow_bans WHERE user_id = $1`
+
+	_, err := r.q.ExecContext(ctx, query, userID)
+
+	return err
+}
+
+// GetShadowBan returns nil without an error when the user is not banned.
+func (r *ModerationRepo) GetShadowBan(ctx context.Context, userID uuid.UUID) (*model.ShadowBan, error) {
+	ban := &model.ShadowBan{}
+
+	query := `
+		SELECT user_id, COALESCE(reason, ''), banned_by, created_at
+		FROM social.shadow_bans
+		WHERE user_id = $1
+	`
+
+	err := r.q.QueryRowContext(ctx, query, userID).Scan(
+		&ban.UserID, &ban.Reason, &ban.BannedBy, &ban.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return ban, nil
+}
+
+// GetReviewedGames lists the games the user has a published review of.
+func (r *ModerationRepo) GetReviewedGames(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+		SELECT DISTINCT game_id
+		FROM social.reviews
+		WHERE user_id = $1 AND status = 'published'
+	`
+
+	rows, err := r.q.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error().Err(err).Msg("moderation_repo: failed to close rows")
+		}
+	}()
+
+	var gameIDs []uuid.UUID
+	for rows.Next() {
+		var gameID uuid.UUID
+		if err := rows.Scan(&gameID); err != nil {
+			return nil, err
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	return gameIDs, rows.Err()
+}
+
+// GetReviewForUpdate locks the review row until the transaction ends.
+func (r *ModerationRepo) GetReviewForUpdate(ctx context.Context, reviewID uuid.UUID) (*model.ModeratedReview, error) {
+	review := &model.ModeratedReview{}
+
+	query := `
+		SELECT id, user_id, game_id, rating, text, created_at, updated_at, version, status
+		FROM social.reviews
+		WHERE id = $1
+		FOR UPDATE
+	`
+
+	err := r.q.QueryRowContext(ctx, query, reviewID).Scan(
+		&review.Id, &review.UserID,
+		&review.GameID, &review.Rating,
+		&review.Text, &review.CreatedAt,
+		&review.UpdatedAt, &review.Version,
+		&review.Status,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrReviewNotFound
+		}
+
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (r *ModerationRepo) SetReviewStatus(ctx context.Context, reviewID uuid.UUID, status string) (*model.ModeratedReview, error) {
+	review := &model.ModeratedReview{}
+
+	query := `
+		UPDATE social.reviews
+		SET status = $2
+		WHERE id = $1
+		RETURNING id, user_id, game_id, rating, text, created_at, updated_at, version, status
+	`
+
+	err := r.q.QueryRowContext(ctx, query, reviewID, status).Scan(
+		&review.Id, &review.UserID,
+		&review.GameID, &review.Rating,
+		&review.Text, &review.CreatedAt,
+		&review.UpdatedAt, &review.Version,
+		&review.Status,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrReviewNotFound
+		}
+
+		return nil, err
+	}
+
+	return review, nil
+}
+
+func (r *ModerationRepo) AppendLog(ctx context.Context, entry *model.ModerationLogEntry) (*model.ModerationLogEntry, error) {
+	created := &model.ModerationLogEntry{}
+
+	query := `
+		INSERT INTO social.moderation_log (actor_id, action, target_type, target_id, reason, before, after)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, actor_id, action, target_type, target_id, COALESCE(reason, ''), before, after, created_at
+	`
+
+	err := r.q.QueryRowContext(ctx, query,
+		entry.ActorID, entry.Action,
+		entry.TargetType, entry.TargetID,
+		entry.Reason, nullableJSON(entry.Before), nullableJSON(entry.After),
+	).Scan(
+		&created.Id, &created.ActorID,
+		&created.Action, &created.TargetType,
+		&created.TargetID, &created.Reason,
+		(*[]byte)(&created.Before), (*[]byte)(&created.After),
+		&created.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (r *ModerationRepo) ListLog(ctx context.Context, filter *model.ModerationLogFilter) ([]*model.ModerationLogEntry, error) {
+	entries := make([]*model.ModerationLogEntry, 0, filter.Limit)
+
+	var (
+		conditions []string
+		args       []any
+	)
+
+	where := func(condition string, arg any) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.ActorID != nil {
+		where("actor_id = $%d", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		where("target_id = $%d", *filter.TargetID)
+	}
+	if filter.TargetType != "" {
+		where("target_type = $%d", filter.TargetType)
+	}
+	if filter.Action != "" {
+		where("action = $%d", filter.Action)
+	}
+	if filter.From != nil {
+		where("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		where("created_at < $%d", *filter.To)
+	}
+
+	query := `
+		SELECT id, actor_id, action, target_type, target_id, COALESCE(reason, ''), before, after, created_at
+		FROM social.moderation_log
+	`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	args = append(args, filter.Limit, filter.Offset)
+	query += fmt.Sprintf(" ORDER BY created_at DESC, id LIMIT $%d OFFSET $%d", len(args)-1, len(args))
+
+	rows, err := r.q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error().Err(err).Msg("moderation_repo: failed to close rows")
+		}
+	}()
+
+	for rows.Next() {
+		entry := &model.ModerationLogEntry{}
+
+		err := rows.Scan(
+			&entry.Id,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.TargetType,
+			&entry.TargetID,
+			&entry.Reason,
+			(*[]byte)(&entry.Before),
+			(*[]byte)(&entry.After),
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func nullableJSON(raw []byte) any {
+	if len(raw) == 0 {
+		return nil
+	}
+
+	return string(raw)
+}
@@ -0,0 +1,258 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupModerationRepoTest(t *testing.T) (*ModerationRepo, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	repo := NewModerationRepo(db)
+
+	return repo, mock, func() {
+		_ = db.Close()
+	}
+}
+
+func TestModerationRepo_SetShadowBan(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID, actorID := uuid.New(), uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO social.shadow_bans`).
+			WithArgs(userID, "spam", actorID).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "reason", "banned_by", "created_at"}).
+				AddRow(userID, "spam", actorID, time.Now()))
+
+		ban, err := repo.SetShadowBan(ctx, userID, "spam", actorID)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, ban.UserID)
+		assert.Equal(t, actorID, ban.BannedBy)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO`).WillReturnError(errors.New("db fail"))
+		_, err := repo.SetShadowBan(ctx, userID, "spam", actorID)
+		assert.Error(t, err)
+	})
+}
+
+func TestModerationRepo_RemoveShadowBan(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	userID := uuid.New()
+
+	mock.ExpectExec(`DELETE FROM social.shadow_bans WHERE user_id = \$1`).
+		WithArgs(userID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.RemoveShadowBan(context.Background(), userID))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerationRepo_GetShadowBan(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("banned", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.shadow_bans WHERE user_id = \$1`).
+			WithArgs(userID).
+			WillReturnRows(sqlmock.NewRows([]string{"user_id", "reason", "banned_by", "created_at"}).
+				AddRow(userID, "", uuid.New(), time.Now()))
+
+		ban, err := repo.GetShadowBan(ctx, userID)
+		assert.NoError(t, err)
+		assert.Equal(t, userID, ban.UserID)
+	})
+
+	t.Run("not banned", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(sql.ErrNoRows)
+		ban, err := repo.GetShadowBan(ctx, userID)
+		assert.NoError(t, err)
+		assert.Nil(t, ban)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db fail"))
+		_, err := repo.GetShadowBan(ctx, userID)
+		assert.Error(t, err)
+	})
+}
+
+var (
+	moderatedReviewColumns = []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version", "status"}
+	logEntryColumns        = []string{"id", "actor_id", "action", "target_type", "target_id", "reason", "before", "after", "created_at"}
+)
+
+func TestModerationRepo_GetReviewedGames(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	userID, gameID := uuid.New(), uuid.New()
+
+	mock.ExpectQuery(`SELECT DISTINCT game_id FROM social.reviews WHERE user_id = \$1 AND status = 'published'`).
+		WithArgs(userID).
+		WillReturnRows(sqlmock.NewRows([]string{"game_id"}).AddRow(gameID))
+
+	gameIDs, err := repo.GetReviewedGames(context.Background(), userID)
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{gameID}, gameIDs)
+}
+
+func TestModerationRepo_WithinTx(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	userID := uuid.New()
+
+	t.Run("commit", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectExec(`DELETE FROM social.shadow_bans`).WithArgs(userID).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		err := repo.WithinTx(ctx, func(repo *ModerationRepo) error {
+			return repo.RemoveShadowBan(ctx, userID)
+		})
+		assert.NoError(t, err)
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		mock.ExpectBegin()
+		mock.ExpectRollback()
+
+		err := repo.WithinTx(ctx, func(repo *ModerationRepo) error {
+			return errors.New("boom")
+		})
+		assert.EqualError(t, err, "boom")
+	})
+
+	t.Run("begin error", func(t *testing.T) {
+		mock.ExpectBegin().WillReturnError(errors.New("no conn"))
+		err := repo.WithinTx(ctx, func(repo *ModerationRepo) error { return nil })
+		assert.Error(t, err)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestModerationRepo_ReviewStatus(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	reviewID := uuid.New()
+	now := time.Now()
+
+	t.Run("get for update", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.reviews WHERE id = \$1 FOR UPDATE`).
+			WithArgs(reviewID).
+			WillReturnRows(sqlmock.NewRows(moderatedReviewColumns).AddRow(reviewID, uuid.New(), uuid.New(), 1, "t", now, now, 1, "published"))
+
+		review, err := repo.GetReviewForUpdate(ctx, reviewID)
+		assert.NoError(t, err)
+		assert.Equal(t, "published", review.Status)
+	})
+
+	t.Run("get missing review", func(t *testing.T) {
+		mock.ExpectQuery(`FOR UPDATE`).WillReturnError(sql.ErrNoRows)
+		_, err := repo.GetReviewForUpdate(ctx, reviewID)
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+	})
+
+	t.Run("set status", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE social.reviews SET status = \$2 WHERE id = \$1`).
+			WithArgs(reviewID, "hidden").
+			WillReturnRows(sqlmock.NewRows(moderatedReviewColumns).AddRow(reviewID, uuid.New(), uuid.New(), 1, "t", now, now, 1, "hidden"))
+
+		review, err := repo.SetReviewStatus(ctx, reviewID, "hidden")
+		assert.NoError(t, err)
+		assert.Equal(t, "hidden", review.Status)
+	})
+
+	t.Run("set status on missing review", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE social.reviews`).WillReturnError(sql.ErrNoRows)
+		_, err := repo.SetReviewStatus(ctx, reviewID, "hidden")
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+	})
+}
+
+func TestModerationRepo_AppendLog(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	entry := &model.ModerationLogEntry{
+		ActorID:    uuid.New(),
+		Action:     model.ModerationActionHideReview,
+		TargetType: model.ModerationTargetReview,
+		TargetID:   uuid.New(),
+		Reason:     "spam",
+		After:      []byte(`{"status":"hidden"}`),
+	}
+
+	mock.ExpectQuery(`INSERT INTO social.moderation_log`).
+		WithArgs(entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, "spam", nil, `{"status":"hidden"}`).
+		WillReturnRows(sqlmock.NewRows(logEntryColumns).
+			AddRow(uuid.New(), entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, "spam", nil, []byte(`{"status":"hidden"}`), time.Now()))
+
+	created, err := repo.AppendLog(context.Background(), entry)
+	assert.NoError(t, err)
+	assert.Nil(t, created.Before)
+	assert.JSONEq(t, `{"status":"hidden"}`, string(created.After))
+}
+
+func TestModerationRepo_ListLog(t *testing.T) {
+	repo, mock, cleanup := setupModerationRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	actorID := uuid.New()
+	from := time.Now().Add(-time.Hour)
+
+	t.Run("with filters", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.moderation_log WHERE actor_id = \$1 AND action = \$2 AND created_at >= \$3 ORDER BY created_at DESC, id LIMIT \$4 OFFSET \$5`).
+			WithArgs(actorID, "hide_review", from, 10, 20).
+			WillReturnRows(sqlmock.NewRows(logEntryColumns).
+				AddRow(uuid.New(), actorID, "hide_review", "review", uuid.New(), "", []byte(`{}`), []byte(`{}`), time.Now()))
+
+		entries, err := repo.ListLog(ctx, &model.ModerationLogFilter{
+			ActorID: &actorID,
+			Action:  "hide_review",
+			From:    &from,
+			Limit:   10,
+			Offset:  20,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, entries, 1)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db fail"))
+		_, err := repo.ListLog(ctx, &model.ModerationLogFilter{Limit: 10})
+		assert.Error(t, err)
+	})
+
+	t.Run("scan error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("x"))
+		_, err := repo.ListLog(ctx, &model.ModerationLogFilter{Limit: 10})
+		assert.Error(t, err)
+	})
+}
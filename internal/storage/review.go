@@ -6,6 +6,7 @@ import (
 	"errors"
 	"social-service/internal/model"
 
+	"github.com/google/uuid"
 	"github.com/lib/pq"
 	"github.com/rs/zerolog/log"
 
@@ -30,16 +31,21 @@ func (r *ReviewRepo) CreateReview(ctx context.Context, req *socialpb.CreateRevie
 	query := `
 		INSERT INTO social.reviews (user_id, game_id, rating, text)
 		VALUES	($1, $2, $3, $4)
-		RETURNING id, user_id, game_id, rating, text, created_at, updated_at
+		RETURNING id, user_id, game_id, rating, text, created_at, updated_at, version
 	`
 
+	ctx, span := startSpan(ctx, "ReviewRepo.CreateReview", query)
+	defer span.End()
+
 	err := r.db.QueryRowContext(ctx, query, req.UserId, req.GameId, req.Rating, req.Text).Scan(
 		&createdReview.Id, &createdReview.UserID,
 		&createdReview.GameID, &createdReview.Rating,
 		&createdReview.Text, &createdReview.CreatedAt,
-		&createdReview.UpdatedAt,
+		&createdReview.UpdatedAt, &createdReview.Version,
 	)
 	if err != nil {
+		spanError(span, err)
+
 		var pqErr *pq.Error
 		if errors.As(err, &pqErr) {
 			if pqErr.Code == "23505" {
@@ -53,20 +59,116 @@ func (r *ReviewRepo) CreateReview(ctx context.Context, req *socialpb.CreateRevie
 	return createdReview, nil
 }
 
-func (r *ReviewRepo) GetReviewsByUser(ctx context.Context, req *socialpb.GetUserReviewsRequest) ([]*model.Review, error) {
+// GetReview returns a published review. Reviews of a shadow-banned user are
+// only returned to viewerID when it is the author.
+func (r *ReviewRepo) GetReview(ctx context.Context, reviewID, viewerID uuid.UUID) (*model.Review, error) {
+	review := &model.Review{}
+
+	query := `
+		SELECT id, user_id, game_id, rating, text, created_at, updated_at, version
+		FROM social.reviews r
+		WHERE r.id = $1 AND r.status = 'published' AND (r.user_id = $2 OR NOT EXISTS (
+			SELECT 1 FROM social.shadow_bans b WHERE b.user_id = r.user_id
+		))
+	`
+
+	ctx, span := startSpan(ctx, "ReviewRepo.GetReview", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query, reviewID, viewerID).Scan(
+		&review.Id, &review.UserID,
+		&review.GameID, &review.Rating,
+		&review.Text, &review.CreatedAt,
+		&review.UpdatedAt, &review.Version,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrReviewNotFound
+		}
+
+		return nil, spanError(span, err)
+	}
+
+	return review, nil
+}
+
+// UpdateReview rewrites the review if it is still at expectedVersion and
+// bumps its version. Otherwise it reports why the update was refused.
+func (r *ReviewRepo) UpdateReview(ctx context.Context, reviewID, userID uuid.UUID, rating int32, text string, expectedVersion int) (*model.Review, error) {
+	review := &model.Review{}
+
+	query := `
+		UPDATE social.reviews
+		SET rating = $3, text = $4, updated_at = NOW(), version = version + 1
+		WHERE id = $1 AND user_id = $2 AND status = 'published' AND version = $5
+		RETURNING id, user_id, game_id, rating, text, created_at, updated_at, version
+	`
+
+	ctx, span := startSpan(ctx, "ReviewRepo.UpdateReview", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query, reviewID, userID, rating, text, expectedVersion).Scan(
+		&review.Id, &review.UserID,
+		&review.GameID, &review.Rating,
+		&review.Text, &review.CreatedAt,
+		&review.UpdatedAt, &review.Version,
+	)
+	if err == nil {
+		return review, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, spanError(span, err)
+	}
+
+	var (
+		authorID uuid.UUID
+		status   string
+	)
+	err = r.db.QueryRowContext(ctx, `SELECT user_id, status FROM social.reviews WHERE id = $1`, reviewID).Scan(&authorID, &status)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, model.ErrReviewNotFound
+		}
+
+		return nil, spanError(span, err)
+	}
+
+	return nil, updateRefusal(authorID, userID, status)
+}
+
+// updateRefusal explains why an update matched no review that exists.
+func updateRefusal(authorID, userID uuid.UUID, status string) error {
+	switch {
+	case authorID != userID:
+		return model.ErrNotReviewEditor
+	case status != model.ReviewStatusPublished:
+		return model.ErrReviewRemoved
+	default:
+		return model.ErrReviewVersionConflict
+	}
+}
+
+// GetReviewsByUser lists the user's reviews. Reviews of a shadow-banned user
+// are only returned when includeShadowBanned is set, i.e. to the user.
+func (r *ReviewRepo) GetReviewsByUser(ctx context.Context, req *socialpb.GetUserReviewsRequest, includeShadowBanned bool) ([]*model.Review, error) {
 	reviews := make([]*model.Review, 0, req.Limit)
 
 	query := `
-		SELECT id, user_id, game_id, rating, text, created_at, updated_at
-		FROM social.reviews
-		WHERE user_id = $1
+		SELECT id, user_id, game_id, rating, text, created_at, updated_at, version
+		FROM social.reviews r
+		WHERE r.user_id = $1 AND r.status = 'published' AND ($4 OR NOT EXISTS (
+			SELECT 1 FROM social.shadow_bans b WHERE b.user_id = r.user_id
+		))
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, req.UserId, req.Limit, req.Offset)
+	ctx, span := startSpan(ctx, "ReviewRepo.GetReviewsByUser", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query, req.UserId, req.Limit, req.Offset, includeShadowBanned)
 	if err != nil {
-		return nil, err
+		return nil, spanError(span, err)
 	}
 
 	defer func() {
@@ -86,6 +188,7 @@ func (r *ReviewRepo) GetReviewsByUser(ctx context.Context, req *socialpb.GetUser
 			&review.Text,
 			&review.CreatedAt,
 			&review.UpdatedAt,
+			&review.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -95,7 +198,7 @@ func (r *ReviewRepo) GetReviewsByUser(ctx context.Context, req *socialpb.GetUser
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, spanError(span, err)
 	}
 
 	return reviews, nil
@@ -105,15 +208,21 @@ func (r *ReviewRepo) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest)
 	reviews := make([]*model.Review, 0, req.Limit)
 
 	query := `
-		SELECT id, user_id, game_id, rating, text, created_at, updated_at
-		FROM social.reviews
+		SELECT id, user_id, game_id, rating, text, created_at, updated_at, version
+		FROM social.reviews r
+		WHERE r.status = 'published' AND NOT EXISTS (
+			SELECT 1 FROM social.shadow_bans b WHERE b.user_id = r.user_id
+		)
 		ORDER BY created_at DESC
 		LIMIT $1
 	`
 
+	ctx, span := startSpan(ctx, "ReviewRepo.GetFeed", query)
+	defer span.End()
+
 	rows, err := r.db.QueryContext(ctx, query, req.Limit)
 	if err != nil {
-		return nil, err
+		return nil, spanError(span, err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -132,6 +241,7 @@ func (r *ReviewRepo) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest)
 			&review.Text,
 			&review.CreatedAt,
 			&review.UpdatedAt,
+			&review.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -141,7 +251,7 @@ func (r *ReviewRepo) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, spanError(span, err)
 	}
 
 	return reviews, nil
@@ -150,25 +260,22 @@ func (r *ReviewRepo) GetFeed(ctx context.Context, req *socialpb.GetFeedRequest)
 func (r *ReviewRepo) GetReviewsByGame(ctx context.Context, req *socialpb.GetGameReviewsRequest) ([]*model.Review, error) {
 	reviews := make([]*model.Review, 0, req.Limit)
 
-	var limit *int32
-
-	if req.Limit == 0 {
-		limit = nil
-	} else {
-		limit = &req.Limit
-	}
-
 	query := `
-		SELECT id, user_id, game_id, rating, text, created_at, updated_at
-		FROM social.reviews
-		WHERE game_id = $1
+		SELECT id, user_id, game_id, rating, text, created_at, updated_at, version
+		FROM social.reviews r
+		WHERE r.game_id = $1 AND r.status = 'published' AND NOT EXISTS (
+			SELECT 1 FROM social.shadow_bans b WHERE b.user_id = r.user_id
+		)
 		ORDER BY created_at DESC
 		LIMIT $2 OFFSET $3
 	`
 
-	rows, err := r.db.QueryContext(ctx, query, req.GameId, limit, req.Offset)
+	ctx, span := startSpan(ctx, "ReviewRepo.GetReviewsByGame", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query, req.GameId, req.Limit, req.Offset)
 	if err != nil {
-		return nil, err
+		return nil, spanError(span, err)
 	}
 	defer func() {
 		if err := rows.Close(); err != nil {
@@ -187,6 +294,7 @@ func (r *ReviewRepo) GetReviewsByGame(ctx context.Context, req *socialpb.GetGame
 			&review.Text,
 			&review.CreatedAt,
 			&review.UpdatedAt,
+			&review.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -196,8 +304,44 @@ func (r *ReviewRepo) GetReviewsByGame(ctx context.Context, req *socialpb.GetGame
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, spanError(span, err)
 	}
 
 	return reviews, nil
 }
+
+// GetRatingSummary aggregates the game's published ratings, ignoring
+// shadow-banned authors. When excludeBombs is set, reviews written during a flagged
+// review-bomb period are left out as well.
+func (r *ReviewRepo) GetRatingSummary(ctx context.Context, gameID uuid.UUID, excludeBombs bool) (*model.RatingSummary, error) {
+	summary := &model.RatingSummary{GameID: gameID}
+
+	query := `
+		SELECT COUNT(*), COALESCE(AVG(r.rating), 0),
+			EXISTS (
+				SELECT 1 FROM social.review_bomb_periods
+				WHERE game_id = $1 AND ended_at IS NULL
+			)
+		FROM social.reviews r
+		WHERE r.game_id = $1 AND r.status = 'published' AND NOT EXISTS (
+			SELECT 1 FROM social.shadow_bans b WHERE b.user_id = r.user_id
+		) AND (NOT $2 OR NOT EXISTS (
+			SELECT 1 FROM social.review_bomb_periods p
+			WHERE p.game_id = r.game_id
+				AND r.created_at >= p.started_at
+				AND (p.ended_at IS NULL OR r.created_at < p.ended_at)
+		))
+	`
+
+	ctx, span := startSpan(ctx, "ReviewRepo.GetRatingSummary", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query, gameID, excludeBombs).Scan(
+		&summary.Count, &summary.Average, &summary.ReviewBomb,
+	)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return summary, nil
+}
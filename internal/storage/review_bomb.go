@@ -0,0 +1,180 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"social-service/internal/model"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog/log"
+)
+
+// ReviewBombRepo keeps review-bomb periods. Every bound is computed from the
+// database's NOW(), the same clock that fills reviews.created_at, so the
+// TIMESTAMP columns compare correctly whatever the session time zone.
+type ReviewBombRepo struct {
+	db *sql.DB
+}
+
+func NewReviewBombRepo(db *sql.DB) *ReviewBombRepo {
+	return &ReviewBombRepo{
+		db: db,
+	}
+}
+
+// GetRatingStats aggregates the published reviews of a game created between
+// since and until ago.
+func (r *ReviewBombRepo) GetRatingStats(ctx context.Context, gameID uuid.UUID, since, until time.Duration, lowRating int) (*model.RatingStats, error) {
+	stats := &model.RatingStats{}
+
+	query := `
+		SELECT COUNT(*), COALESCE(AVG(rating), 0), COUNT(*) FILTER (WHERE rating <= $4)
+		FROM social.reviews r
+		WHERE r.game_id = $1 AND r.status = 'published'
+			AND r.created_at >= NOW() - make_interval(secs => $2)
+			AND r.created_at < NOW() - make_interval(secs => $3)
+			AND NOT EXISTS (
+				SELECT 1 FROM social.shadow_bans b WHERE b.user_id = r.user_id
+			)
+	`
+
+	ctx, span := startSpan(ctx, "ReviewBombRepo.GetRatingStats", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query, gameID, since.Seconds(), until.Seconds(), lowRating).Scan(
+		&stats.Count, &stats.Average, &stats.LowRating,
+	)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return stats, nil
+}
+
+func (r *ReviewBombRepo) GetActivePeriod(ctx context.Context, gameID uuid.UUID) (*model.ReviewBombPeriod, error) {
+	period := &model.ReviewBombPeriod{}
+
+	query := `
+		SELECT id, game_id, started_at, ended_at, window_reviews, window_average,
+			baseline_reviews, baseline_average, created_at
+		FROM social.review_bomb_periods
+		WHERE game_id = $1 AND ended_at IS NULL
+	`
+
+	ctx, span := startSpan(ctx, "ReviewBombRepo.GetActivePeriod", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query, gameID).Scan(
+		&period.Id, &period.GameID,
+		&period.StartedAt, &period.EndedAt,
+		&period.WindowReviews, &period.WindowAverage,
+		&period.BaselineReviews, &period.BaselineAverage,
+		&period.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, spanError(span, err)
+	}
+
+	return period, nil
+}
+
+// OpenPeriod flags the game as being review-bombed since window ago; the
+// StartedAt of period is ignored. It returns nil without an error when
+// another instance has already opened a period for the game.
+func (r *ReviewBombRepo) OpenPeriod(ctx context.Context, period *model.ReviewBombPeriod, window time.Duration) (*model.ReviewBombPeriod, error) {
+	created := &model.ReviewBombPeriod{}
+
+	query := `
+		INSERT INTO social.review_bomb_periods
+			(game_id, started_at, window_reviews, window_average, baseline_reviews, baseline_average)
+		VALUES ($1, NOW() - make_interval(secs => $2), $3, $4, $5, $6)
+		ON CONFLICT (game_id) WHERE ended_at IS NULL DO NOTHING
+		RETURNING id, game_id, started_at, ended_at, window_reviews, window_average,
+			baseline_reviews, baseline_average, created_at
+	`
+
+	ctx, span := startSpan(ctx, "ReviewBombRepo.OpenPeriod", query)
+	defer span.End()
+
+	err := r.db.QueryRowContext(ctx, query,
+		period.GameID, window.Seconds(),
+		period.WindowReviews, period.WindowAverage,
+		period.BaselineReviews, period.BaselineAverage,
+	).Scan(
+		&created.Id, &created.GameID,
+		&created.StartedAt, &created.EndedAt,
+		&created.WindowReviews, &created.WindowAverage,
+		&created.BaselineReviews, &created.BaselineAverage,
+		&created.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, spanError(span, err)
+	}
+
+	return created, nil
+}
+
+// ClosePeriod ends the period now.
+func (r *ReviewBombRepo) ClosePeriod(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE social.review_bomb_periods
+		SET ended_at = NOW()
+		WHERE id = $1 AND ended_at IS NULL
+	`
+
+	ctx, span := startSpan(ctx, "ReviewBombRepo.ClosePeriod", query)
+	defer span.End()
+
+	if _, err := r.db.ExecContext(ctx, query, id); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// GetBombedGames lists the games with an open review-bomb period.
+func (r *ReviewBombRepo) GetBombedGames(ctx context.Context) ([]uuid.UUID, error) {
+	query := `
+		SELECT game_id
+		FROM social.review_bomb_periods
+		WHERE ended_at IS NULL
+	`
+
+	ctx, span := startSpan(ctx, "ReviewBombRepo.GetBombedGames", query)
+	defer span.End()
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Error().Err(err).Msg("review_bomb_repo: failed to close rows")
+		}
+	}()
+
+	var gameIDs []uuid.UUID
+	for rows.Next() {
+		var gameID uuid.UUID
+		if err := rows.Scan(&gameID); err != nil {
+			return nil, spanError(span, err)
+		}
+		gameIDs = append(gameIDs, gameID)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return gameIDs, nil
+}
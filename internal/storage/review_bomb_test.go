@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"social-service/internal/model"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupReviewBombRepoTest(t *testing.T) (*ReviewBombRepo, sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+
+	repo := NewReviewBombRepo(db)
+
+	return repo, mock, func() {
+		_ = db.Close()
+	}
+}
+
+var reviewBombColumns = []string{
+	"id", "game_id", "started_at", "ended_at", "window_reviews", "window_average",
+	"baseline_reviews", "baseline_average", "created_at",
+}
+
+func TestReviewBombRepo_GetRatingStats(t *testing.T) {
+	repo, mock, cleanup := setupReviewBombRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	gameID := uuid.New()
+	since, until := 2*time.Hour, time.Hour
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.reviews r WHERE r.game_id = \$1 AND r.status = 'published' `+
+			`AND r.created_at >= NOW\(\) - make_interval\(secs => \$2\) AND r.created_at < NOW\(\) - make_interval\(secs => \$3\)`).
+			WithArgs(gameID, 7200.0, 3600.0, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "avg", "low"}).AddRow(10, 12.5, 9))
+
+		stats, err := repo.GetRatingStats(ctx, gameID, since, until, 20)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.RatingStats{Count: 10, Average: 12.5, LowRating: 9}, stats)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db fail"))
+		_, err := repo.GetRatingStats(ctx, gameID, since, until, 20)
+		assert.Error(t, err)
+	})
+}
+
+func TestReviewBombRepo_GetActivePeriod(t *testing.T) {
+	repo, mock, cleanup := setupReviewBombRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	gameID := uuid.New()
+
+	t.Run("found", func(t *testing.T) {
+		id := uuid.New()
+		mock.ExpectQuery(`WHERE game_id = \$1 AND ended_at IS NULL`).
+			WithArgs(gameID).
+			WillReturnRows(sqlmock.NewRows(reviewBombColumns).
+				AddRow(id, gameID, time.Now(), nil, 100, 3.0, 10, 80.0, time.Now()))
+
+		period, err := repo.GetActivePeriod(ctx, gameID)
+		assert.NoError(t, err)
+		assert.Equal(t, id, period.Id)
+		assert.Nil(t, period.EndedAt)
+	})
+
+	t.Run("none", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(sql.ErrNoRows)
+		period, err := repo.GetActivePeriod(ctx, gameID)
+		assert.NoError(t, err)
+		assert.Nil(t, period)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db fail"))
+		_, err := repo.GetActivePeriod(ctx, gameID)
+		assert.Error(t, err)
+	})
+}
+
+func TestReviewBombRepo_OpenPeriod(t *testing.T) {
+	repo, mock, cleanup := setupReviewBombRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	period := &model.ReviewBombPeriod{
+		GameID:          uuid.New(),
+		StartedAt:       time.Now(),
+		WindowReviews:   100,
+		WindowAverage:   3,
+		BaselineReviews: 10,
+		BaselineAverage: 80,
+	}
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO social.review_bomb_periods`).
+			WithArgs(period.GameID, 3600.0, 100, 3.0, 10, 80.0).
+			WillReturnRows(sqlmock.NewRows(reviewBombColumns).
+				AddRow(uuid.New(), period.GameID, period.StartedAt, nil, 100, 3.0, 10, 80.0, time.Now()))
+
+		created, err := repo.OpenPeriod(ctx, period, time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, period.GameID, created.GameID)
+	})
+
+	t.Run("already open", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO`).WillReturnRows(sqlmock.NewRows(reviewBombColumns))
+		created, err := repo.OpenPeriod(ctx, period, time.Hour)
+		assert.NoError(t, err)
+		assert.Nil(t, created)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`INSERT INTO`).WillReturnError(errors.New("db fail"))
+		_, err := repo.OpenPeriod(ctx, period, time.Hour)
+		assert.Error(t, err)
+	})
+}
+
+func TestReviewBombRepo_ClosePeriod(t *testing.T) {
+	repo, mock, cleanup := setupReviewBombRepoTest(t)
+	defer cleanup()
+
+	id := uuid.New()
+
+	mock.ExpectExec(`UPDATE social.review_bomb_periods SET ended_at = NOW\(\)`).
+		WithArgs(id).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.ClosePeriod(context.Background(), id))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReviewBombRepo_GetBombedGames(t *testing.T) {
+	repo, mock, cleanup := setupReviewBombRepoTest(t)
+	defer cleanup()
+
+	gameID := uuid.New()
+
+	mock.ExpectQuery(`SELECT game_id FROM social.review_bomb_periods WHERE ended_at IS NULL`).
+		WillReturnRows(sqlmock.NewRows([]string{"game_id"}).AddRow(gameID))
+
+	gameIDs, err := repo.GetBombedGames(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{gameID}, gameIDs)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
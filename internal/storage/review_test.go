@@ -2,7 +2,9 @@ package storage
 
 import (
 	"context"
+	"database/sql"
 	"errors"
+	"social-service/internal/model"
 	"testing"
 	"time"
 
@@ -38,12 +40,12 @@ func TestReviewRepo_CreateReview(t *testing.T) {
 		Text:   "Great game!",
 	}
 
-	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}
+	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}
 
 	t.Run("success", func(t *testing.T) {
 		now := time.Now()
 		rows := sqlmock.NewRows(columns).
-			AddRow(uuid.New().String(), req.UserId, req.GameId, req.Rating, req.Text, now, now)
+			AddRow(uuid.New().String(), req.UserId, req.GameId, req.Rating, req.Text, now, now, 1)
 
 		mock.ExpectQuery(`INSERT INTO social.reviews`).
 			WithArgs(req.UserId, req.GameId, req.Rating, req.Text).
@@ -79,35 +81,36 @@ func TestReviewRepo_GetReviewsByUser(t *testing.T) {
 	ctx := context.Background()
 	userID := uuid.New().String()
 	req := &socialpb.GetUserReviewsRequest{UserId: userID, Limit: 10, Offset: 0}
-	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}
+	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}
 
 	t.Run("success", func(t *testing.T) {
 		rows := sqlmock.NewRows(columns).
-			AddRow(uuid.New().String(), userID, uuid.New().String(), 80, "Nice", time.Now(), time.Now())
-		mock.ExpectQuery(`SELECT (.+) FROM social.reviews WHERE user_id = \$1`).WillReturnRows(rows)
-		res, err := repo.GetReviewsByUser(ctx, req)
+			AddRow(uuid.New().String(), userID, uuid.New().String(), 80, "Nice", time.Now(), time.Now(), 4)
+		mock.ExpectQuery(`SELECT (.+) FROM social.reviews r WHERE r.user_id = \$1`).WillReturnRows(rows)
+		res, err := repo.GetReviewsByUser(ctx, req, false)
 		assert.NoError(t, err)
-		assert.Len(t, res, 1)
+		require.Len(t, res, 1)
+		assert.Equal(t, 4, res[0].Version)
 	})
 
 	t.Run("query error", func(t *testing.T) {
 		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("fail"))
-		_, err := repo.GetReviewsByUser(ctx, req)
+		_, err := repo.GetReviewsByUser(ctx, req, false)
 		assert.Error(t, err)
 	})
 
 	t.Run("scan error", func(t *testing.T) {
 		rows := sqlmock.NewRows([]string{"id"}).AddRow("not-uuid")
 		mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
-		_, err := repo.GetReviewsByUser(ctx, req)
+		_, err := repo.GetReviewsByUser(ctx, req, false)
 		assert.Error(t, err)
 	})
 
 	t.Run("rows error", func(t *testing.T) {
-		rows := sqlmock.NewRows(columns).AddRow(uuid.New().String(), userID, uuid.New().String(), 80, "Nice", time.Now(), time.Now()).
+		rows := sqlmock.NewRows(columns).AddRow(uuid.New().String(), userID, uuid.New().String(), 80, "Nice", time.Now(), time.Now(), 1).
 			RowError(0, errors.New("iteration error"))
 		mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
-		_, err := repo.GetReviewsByUser(ctx, req)
+		_, err := repo.GetReviewsByUser(ctx, req, false)
 		assert.Error(t, err)
 	})
 }
@@ -118,12 +121,12 @@ func TestReviewRepo_GetFeed(t *testing.T) {
 
 	ctx := context.Background()
 	req := &socialpb.GetFeedRequest{Limit: 5}
-	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}
+	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}
 
 	t.Run("success", func(t *testing.T) {
 		rows := sqlmock.NewRows(columns).
-			AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 50, "T1", time.Now(), time.Now())
-		mock.ExpectQuery(`SELECT (.+) FROM social.reviews ORDER BY created_at DESC LIMIT \$1`).WillReturnRows(rows)
+			AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 50, "T1", time.Now(), time.Now(), 1)
+		mock.ExpectQuery(`SELECT (.+) FROM social.reviews r WHERE r.status = 'published' AND NOT EXISTS \( SELECT 1 FROM social.shadow_bans (.+) LIMIT \$1`).WillReturnRows(rows)
 		res, err := repo.GetFeed(ctx, req)
 		assert.NoError(t, err)
 		assert.Len(t, res, 1)
@@ -143,7 +146,7 @@ func TestReviewRepo_GetFeed(t *testing.T) {
 	})
 
 	t.Run("rows error", func(t *testing.T) {
-		rows := sqlmock.NewRows(columns).AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 5, "T", time.Now(), time.Now()).
+		rows := sqlmock.NewRows(columns).AddRow(uuid.New().String(), uuid.New().String(), uuid.New().String(), 5, "T", time.Now(), time.Now(), 1).
 			RowError(0, errors.New("stream error"))
 		mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 		_, err := repo.GetFeed(ctx, req)
@@ -157,13 +160,13 @@ func TestReviewRepo_GetReviewsByGame(t *testing.T) {
 
 	ctx := context.Background()
 	gameID := uuid.New().String()
-	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at"}
+	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}
 
 	t.Run("success with limit", func(t *testing.T) {
 		req := &socialpb.GetGameReviewsRequest{GameId: gameID, Limit: 10, Offset: 0}
 		rows := sqlmock.NewRows(columns).
-			AddRow(uuid.New().String(), uuid.New().String(), gameID, 50, "T1", time.Now(), time.Now())
-		mock.ExpectQuery(`WHERE game_id = \$1`).WillReturnRows(rows)
+			AddRow(uuid.New().String(), uuid.New().String(), gameID, 50, "T1", time.Now(), time.Now(), 1)
+		mock.ExpectQuery(`WHERE r.game_id = \$1 AND r.status = 'published' AND NOT EXISTS`).WillReturnRows(rows)
 		res, err := repo.GetReviewsByGame(ctx, req)
 		assert.NoError(t, err)
 		assert.NotNil(t, res)
@@ -186,17 +189,96 @@ func TestReviewRepo_GetReviewsByGame(t *testing.T) {
 
 	t.Run("rows error during iteration", func(t *testing.T) {
 		req := &socialpb.GetGameReviewsRequest{GameId: gameID, Limit: 10}
-		rows := sqlmock.NewRows(columns).AddRow(uuid.New().String(), uuid.New().String(), gameID, 5, "T", time.Now(), time.Now()).
+		rows := sqlmock.NewRows(columns).AddRow(uuid.New().String(), uuid.New().String(), gameID, 5, "T", time.Now(), time.Now(), 1).
 			RowError(0, errors.New("broken pipe"))
 		mock.ExpectQuery(`SELECT`).WillReturnRows(rows)
 		_, err := repo.GetReviewsByGame(ctx, req)
 		assert.Error(t, err)
 	})
 
-	t.Run("success with zero limit", func(t *testing.T) {
+	t.Run("zero limit is passed through", func(t *testing.T) {
 		req := &socialpb.GetGameReviewsRequest{GameId: gameID, Limit: 0, Offset: 0}
-		mock.ExpectQuery(`WHERE game_id = \$1`).WithArgs(gameID, nil, 0).WillReturnRows(sqlmock.NewRows(columns))
+		mock.ExpectQuery(`WHERE r.game_id = \$1 AND r.status = 'published' AND NOT EXISTS`).WithArgs(gameID, 0, 0).WillReturnRows(sqlmock.NewRows(columns))
 		_, err := repo.GetReviewsByGame(ctx, req)
 		assert.NoError(t, err)
 	})
 }
+
+func TestReviewRepo_GetRatingSummary(t *testing.T) {
+	repo, mock, cleanup := setupReviewRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	gameID := uuid.New()
+
+	t.Run("success", func(t *testing.T) {
+		mock.ExpectQuery(`FROM social.reviews r WHERE r.game_id = \$1`).
+			WithArgs(gameID, true).
+			WillReturnRows(sqlmock.NewRows([]string{"count", "avg", "exists"}).AddRow(4, 77.5, true))
+
+		summary, err := repo.GetRatingSummary(ctx, gameID, true)
+		assert.NoError(t, err)
+		assert.Equal(t, gameID, summary.GameID)
+		assert.Equal(t, 4, summary.Count)
+		assert.Equal(t, 77.5, summary.Average)
+		assert.True(t, summary.ReviewBomb)
+	})
+
+	t.Run("query error", func(t *testing.T) {
+		mock.ExpectQuery(`SELECT`).WillReturnError(errors.New("db fail"))
+		_, err := repo.GetRatingSummary(ctx, gameID, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestReviewRepo_UpdateReview(t *testing.T) {
+	repo, mock, cleanup := setupReviewRepoTest(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	reviewID, userID := uuid.New(), uuid.New()
+	columns := []string{"id", "user_id", "game_id", "rating", "text", "created_at", "updated_at", "version"}
+
+	t.Run("success", func(t *testing.T) {
+		now := time.Now()
+		mock.ExpectQuery(`UPDATE social.reviews`).
+			WithArgs(reviewID, userID, int32(80), "better", 2).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow(reviewID, userID, uuid.New(), 80, "better", now, now, 3))
+
+		review, err := repo.UpdateReview(ctx, reviewID, userID, 80, "better", 2)
+		require.NoError(t, err)
+		assert.Equal(t, 3, review.Version)
+	})
+
+	refusals := []struct {
+		name   string
+		author uuid.UUID
+		status string
+		want   error
+	}{
+		{"stale version", userID, model.ReviewStatusPublished, model.ErrReviewVersionConflict},
+		{"other author", uuid.New(), model.ReviewStatusPublished, model.ErrNotReviewEditor},
+		{"removed review", userID, model.ReviewStatusHidden, model.ErrReviewRemoved},
+	}
+	for _, tt := range refusals {
+		t.Run(tt.name, func(t *testing.T) {
+			mock.ExpectQuery(`UPDATE social.reviews`).WillReturnError(sql.ErrNoRows)
+			mock.ExpectQuery(`SELECT user_id, status FROM social.reviews`).
+				WithArgs(reviewID).
+				WillReturnRows(sqlmock.NewRows([]string{"user_id", "status"}).AddRow(tt.author, tt.status))
+
+			_, err := repo.UpdateReview(ctx, reviewID, userID, 80, "better", 2)
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	t.Run("missing review", func(t *testing.T) {
+		mock.ExpectQuery(`UPDATE social.reviews`).WillReturnError(sql.ErrNoRows)
+		mock.ExpectQuery(`SELECT user_id, status`).WillReturnError(sql.ErrNoRows)
+
+		_, err := repo.UpdateReview(ctx, reviewID, userID, 80, "better", 2)
+		assert.ErrorIs(t, err, model.ErrReviewNotFound)
+	})
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,32 @@
+package storage
+
+import (
+	"context"
+	"social-service/internal/model"
+
+	"github.com/google/uuid"
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+)
+
+// ReviewStore persists reviews. ReviewRepo is the Postgres implementation and
+// MemoryReviewStore the in-memory one; both pass the same conformance suite.
+type ReviewStore interface {
+	// CreateReview returns errs.ErrReviewExists when the user has already
+	// reviewed the game.
+	CreateReview(ctx context.Context, req *socialpb.CreateReviewRequest) (*model.Review, error)
+	// GetReview returns model.ErrReviewNotFound unless the review is visible
+	// to viewerID.
+	GetReview(ctx context.Context, reviewID, viewerID uuid.UUID) (*model.Review, error)
+	// UpdateReview applies only while the review is at expectedVersion and
+	// returns model.ErrReviewVersionConflict once it has moved on.
+	UpdateReview(ctx context.Context, reviewID, userID uuid.UUID, rating int32, text string, expectedVersion int) (*model.Review, error)
+	GetReviewsByUser(ctx context.Context, req *socialpb.GetUserReviewsRequest, includeShadowBanned bool) ([]*model.Review, error)
+	GetFeed(ctx context.Context, req *socialpb.GetFeedRequest) ([]*model.Review, error)
+	GetReviewsByGame(ctx context.Context, req *socialpb.GetGameReviewsRequest) ([]*model.Review, error)
+	GetRatingSummary(ctx context.Context, gameID uuid.UUID, excludeBombs bool) (*model.RatingSummary, error)
+}
+
+var (
+	_ ReviewStore = (*ReviewRepo)(nil)
+	_ ReviewStore = (*MemoryReviewStore)(nil)
+)
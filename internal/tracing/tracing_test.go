@@ -0,0 +1,63 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestInit(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		shutdown, err := Init(context.Background(), ExporterNone, 1)
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		shutdown, err := Init(context.Background(), ExporterStdout, 1)
+		require.NoError(t, err)
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		_, err := Init(context.Background(), "jaeger", 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestKafkaPropagation(t *testing.T) {
+	_, err := Init(context.Background(), ExporterNone, 1)
+	require.NoError(t, err)
+
+	provider := sdktrace.NewTracerProvider()
+	ctx, span := provider.Tracer("test").Start(context.Background(), "publish")
+	defer span.End()
+
+	msg := kafka.Message{Headers: []kafka.Header{{Key: "other", Value: []byte("x")}}}
+	InjectKafka(ctx, &msg)
+
+	carrier := KafkaHeaderCarrier{Headers: &msg.Headers}
+	assert.NotEmpty(t, carrier.Get("traceparent"))
+	assert.ElementsMatch(t, []string{"other", "traceparent"}, carrier.Keys())
+
+	extracted := trace.SpanContextFromContext(ExtractKafka(context.Background(), &msg))
+	assert.Equal(t, span.SpanContext().TraceID(), extracted.TraceID())
+	assert.Equal(t, span.SpanContext().SpanID(), extracted.SpanID())
+}
+
+func TestKafkaHeaderCarrier_Set(t *testing.T) {
+	var headers []kafka.Header
+	carrier := KafkaHeaderCarrier{Headers: &headers}
+
+	carrier.Set("traceparent", "a")
+	carrier.Set("traceparent", "b")
+
+	assert.Len(t, headers, 1)
+	assert.Equal(t, "b", carrier.Get("traceparent"))
+	assert.Empty(t, carrier.Get("missing"))
+}
@@ -20,3 +20,17 @@ func GetUserID(ctx context.Context) (string, error) {
 
 	return values[0], nil
 }
+
+func GetUserRole(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", errs.ErrInvalidMetadata
+	}
+
+	values := md.Get("x-user-role")
+	if len(values) == 0 {
+		return "", errs.ErrMetadataNotFound
+	}
+
+	return values[0], nil
+}
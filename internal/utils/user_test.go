@@ -49,3 +49,31 @@ func TestGetUserID(t *testing.T) {
 		assert.ErrorIs(t, err, errs.ErrMetadataNotFound)
 	})
 }
+
+func TestGetUserRole(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		md := metadata.Pairs("x-user-role", "admin")
+		ctx := metadata.NewIncomingContext(context.Background(), md)
+
+		role, err := GetUserRole(ctx)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "admin", role)
+	})
+
+	t.Run("no metadata in context", func(t *testing.T) {
+		role, err := GetUserRole(context.Background())
+
+		assert.Empty(t, role)
+		assert.ErrorIs(t, err, errs.ErrInvalidMetadata)
+	})
+
+	t.Run("metadata exists but key is missing", func(t *testing.T) {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "u1"))
+
+		role, err := GetUserRole(ctx)
+
+		assert.Empty(t, role)
+		assert.ErrorIs(t, err, errs.ErrMetadataNotFound)
+	})
+}
@@ -0,0 +1,124 @@
+package validation
+
+import (
+	"context"
+	moderationpb "social-service/gen/go/moderation"
+	reviewspb "social-service/gen/go/reviews"
+	"social-service/internal/model"
+
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"google.golang.org/grpc"
+)
+
+// maxReasonLength bounds moderator reasons, appeal messages and notes.
+const maxReasonLength = 1000
+
+// maxStreamFilterIDs bounds the games and users a live feed filters on.
+const maxStreamFilterIDs = 1000
+
+type Limits struct {
+	MaxPageSize   int
+	MaxTextLength int
+}
+
+// Validate checks req and returns nil for valid requests and for message
+// types without rules.
+func (l Limits) Validate(req any) error {
+	var v Violations
+
+	switch req := req.(type) {
+	case *socialpb.CreateReviewRequest:
+		v.UUID("game_id", req.GameId)
+		v.Range("rating", int64(req.Rating), model.MinRating, model.MaxRating)
+		v.MaxLength("text", req.Text, l.MaxTextLength)
+	case *socialpb.GetGameReviewsRequest:
+		v.UUID("game_id", req.GameId)
+		v.Page(req.Limit, req.Offset, l.MaxPageSize)
+	case *socialpb.GetUserReviewsRequest:
+		v.UUID("user_id", req.UserId)
+		v.Page(req.Limit, req.Offset, l.MaxPageSize)
+	case *socialpb.GetFeedRequest:
+		v.Page(req.Limit, 0, l.MaxPageSize)
+
+	case *reviewspb.GetReviewRequest:
+		v.UUID("review_id", req.ReviewId)
+	case *reviewspb.UpdateReviewRequest:
+		v.UUID("review_id", req.ReviewId)
+		v.Range("rating", int64(req.Rating), model.MinRating, model.MaxRating)
+		v.MaxLength("text", req.Text, l.MaxTextLength)
+		if req.Etag == "" && req.ExpectedVersion < 1 {
+			v.Add("expected_version", "is required unless etag is set")
+		}
+	case *reviewspb.StreamReviewsRequest:
+		v.UUIDs("game_ids", req.GameIds, maxStreamFilterIDs)
+		v.UUIDs("user_ids", req.UserIds, maxStreamFilterIDs)
+
+	case *moderationpb.SetShadowBanRequest:
+		v.UUID("user_id", req.UserId)
+		v.MaxLength("reason", req.Reason, maxReasonLength)
+	case *moderationpb.GetShadowBanRequest:
+		v.UUID("user_id", req.UserId)
+	case *moderationpb.ModerateReviewRequest:
+		v.UUID("review_id", req.ReviewId)
+		v.MaxLength("reason", req.Reason, maxReasonLength)
+	case *moderationpb.ListModerationLogRequest:
+		v.OptionalUUID("actor_id", req.ActorId)
+		v.OptionalUUID("target_id", req.TargetId)
+		v.Page(req.Limit, req.Offset, l.MaxPageSize)
+		if req.From != nil && req.To != nil && req.From.AsTime().After(req.To.AsTime()) {
+			v.Add("from", "must not be after to")
+		}
+	case *moderationpb.FileAppealRequest:
+		v.UUID("review_id", req.ReviewId)
+		v.Required("message", req.Message)
+		v.MaxLength("message", req.Message, maxReasonLength)
+	case *moderationpb.ListAppealsRequest:
+		switch req.Status {
+		case "", model.AppealStatusPending, model.AppealStatusUpheld, model.AppealStatusReinstated:
+		default:
+			v.Add("status", "must be pending, upheld or reinstated")
+		}
+		v.Page(req.Limit, req.Offset, l.MaxPageSize)
+	case *moderationpb.ResolveAppealRequest:
+		v.UUID("appeal_id", req.AppealId)
+		if req.Outcome != moderationpb.AppealOutcome_APPEAL_OUTCOME_UPHELD && req.Outcome != moderationpb.AppealOutcome_APPEAL_OUTCOME_REINSTATED {
+			v.Add("outcome", "must be upheld or reinstated")
+		}
+		v.MaxLength("note", req.Note, maxReasonLength)
+	}
+
+	return v.Err()
+}
+
+// UnaryServerInterceptor rejects invalid requests with InvalidArgument before
+// the handler runs.
+func UnaryServerInterceptor(limits Limits) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := limits.Validate(req); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor validates each message a streaming RPC receives,
+// failing the stream with InvalidArgument on the first invalid one.
+func StreamServerInterceptor(limits Limits) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &validatingStream{ServerStream: ss, limits: limits})
+	}
+}
+
+type validatingStream struct {
+	grpc.ServerStream
+	limits Limits
+}
+
+func (s *validatingStream) RecvMsg(m any) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+
+	return s.limits.Validate(m)
+}
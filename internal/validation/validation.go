@@ -0,0 +1,109 @@
+// Package validation checks request fields before they reach the handlers and
+// reports every problem at once as google.rpc.BadRequest field violations.
+package validation
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Violations collects the field violations of one request.
+type Violations struct {
+	list []*errdetails.BadRequest_FieldViolation
+}
+
+// Add records a violation of field, named as in the proto message.
+func (v *Violations) Add(field, description string) {
+	v.list = append(v.list, &errdetails.BadRequest_FieldViolation{
+		Field:       field,
+		Description: description,
+	})
+}
+
+// UUID requires value to be a UUID.
+func (v *Violations) UUID(field, value string) {
+	if value == "" {
+		v.Add(field, "is required")
+		return
+	}
+	if _, err := uuid.Parse(value); err != nil {
+		v.Add(field, "must be a UUID")
+	}
+}
+
+// OptionalUUID accepts an empty value or a UUID.
+func (v *Violations) OptionalUUID(field, value string) {
+	if value != "" {
+		v.UUID(field, value)
+	}
+}
+
+// UUIDs requires at most max values, each a UUID.
+func (v *Violations) UUIDs(field string, values []string, max int) {
+	if len(values) > max {
+		v.Add(field, fmt.Sprintf("must have at most %d entries, got %d", max, len(values)))
+		return
+	}
+	for i, value := range values {
+		v.UUID(fmt.Sprintf("%s[%d]", field, i), value)
+	}
+}
+
+// Required rejects an empty value.
+func (v *Violations) Required(field, value string) {
+	if value == "" {
+		v.Add(field, "is required")
+	}
+}
+
+// Range requires min <= value <= max.
+func (v *Violations) Range(field string, value, min, max int64) {
+	if value < min || value > max {
+		v.Add(field, fmt.Sprintf("must be between %d and %d", min, max))
+	}
+}
+
+// MaxLength limits value to max characters.
+func (v *Violations) MaxLength(field, value string, max int) {
+	if !utf8.ValidString(value) {
+		v.Add(field, "must be valid UTF-8")
+		return
+	}
+	if n := utf8.RuneCountInString(value); n > max {
+		v.Add(field, fmt.Sprintf("must be at most %d characters, got %d", max, n))
+	}
+}
+
+// Page checks the limit and offset of a paginated request. A limit of 0
+// leaves the page size to the service.
+func (v *Violations) Page(limit, offset int32, maxPageSize int) {
+	v.Range("limit", int64(limit), 0, int64(maxPageSize))
+	if offset < 0 {
+		v.Add("offset", "must not be negative")
+	}
+}
+
+// Err returns nil when nothing was recorded, otherwise an InvalidArgument
+// status carrying a BadRequest detail.
+func (v *Violations) Err() error {
+	if len(v.list) == 0 {
+		return nil
+	}
+
+	msg := "invalid " + v.list[0].Field + ": " + v.list[0].Description
+	if len(v.list) > 1 {
+		msg += fmt.Sprintf(" (and %d more)", len(v.list)-1)
+	}
+
+	st, err := status.New(codes.InvalidArgument, msg).WithDetails(&errdetails.BadRequest{FieldViolations: v.list})
+	if err != nil {
+		return status.Error(codes.InvalidArgument, msg)
+	}
+
+	return st.Err()
+}
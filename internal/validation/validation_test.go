@@ -0,0 +1,203 @@
+package validation
+
+import (
+	"context"
+	moderationpb "social-service/gen/go/moderation"
+	reviewspb "social-service/gen/go/reviews"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	socialpb "github.com/viktoralyoshin/playhub-proto/gen/go/social"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var testLimits = Limits{MaxPageSize: 50, MaxTextLength: 10}
+
+// violatedFields returns the fields named in the BadRequest detail of err.
+func violatedFields(t *testing.T, err error) []string {
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			fields := make([]string, 0, len(badRequest.FieldViolations))
+			for _, violation := range badRequest.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+			return fields
+		}
+	}
+
+	t.Fatal("no BadRequest detail")
+	return nil
+}
+
+func TestLimits_Validate(t *testing.T) {
+	id := uuid.NewString()
+
+	tests := []struct {
+		name       string
+		req        any
+		wantFields []string
+	}{
+		{
+			name: "valid review",
+			req:  &socialpb.CreateReviewRequest{GameId: id, Rating: 100, Text: "Great game"},
+		},
+		{
+			name:       "every review field wrong",
+			req:        &socialpb.CreateReviewRequest{GameId: "42", Rating: 101, Text: strings.Repeat("a", 11)},
+			wantFields: []string{"game_id", "rating", "text"},
+		},
+		{
+			name:       "text length counts characters",
+			req:        &socialpb.CreateReviewRequest{GameId: id, Rating: 0, Text: strings.Repeat("я", 10)},
+			wantFields: nil,
+		},
+		{
+			name:       "negative rating",
+			req:        &socialpb.CreateReviewRequest{GameId: id, Rating: -1},
+			wantFields: []string{"rating"},
+		},
+		{
+			name:       "page too large",
+			req:        &socialpb.GetGameReviewsRequest{GameId: id, Limit: 51, Offset: -1},
+			wantFields: []string{"limit", "offset"},
+		},
+		{
+			name:       "missing user",
+			req:        &socialpb.GetUserReviewsRequest{Limit: 10},
+			wantFields: []string{"user_id"},
+		},
+		{
+			name:       "negative feed limit",
+			req:        &socialpb.GetFeedRequest{Limit: -5},
+			wantFields: []string{"limit"},
+		},
+		{
+			name: "update by etag",
+			req:  &reviewspb.UpdateReviewRequest{ReviewId: id, Rating: 70, Etag: `"2"`},
+		},
+		{
+			name:       "update without a version",
+			req:        &reviewspb.UpdateReviewRequest{ReviewId: id, Rating: 70},
+			wantFields: []string{"expected_version"},
+		},
+		{
+			name: "stream everything",
+			req:  &reviewspb.StreamReviewsRequest{},
+		},
+		{
+			name:       "stream filter ids",
+			req:        &reviewspb.StreamReviewsRequest{GameIds: []string{id, "42"}, UserIds: make([]string, maxStreamFilterIDs+1)},
+			wantFields: []string{"game_ids[1]", "user_ids"},
+		},
+		{
+			name: "log filters are optional",
+			req:  &moderationpb.ListModerationLogRequest{Limit: 50},
+		},
+		{
+			name: "log range reversed",
+			req: &moderationpb.ListModerationLogRequest{
+				ActorId: "admin",
+				From:    timestamppb.New(time.Now()),
+				To:      timestamppb.New(time.Now().Add(-time.Hour)),
+			},
+			wantFields: []string{"actor_id", "from"},
+		},
+		{
+			name:       "empty appeal",
+			req:        &moderationpb.FileAppealRequest{ReviewId: id},
+			wantFields: []string{"message"},
+		},
+		{
+			name:       "unknown appeal status",
+			req:        &moderationpb.ListAppealsRequest{Status: "open"},
+			wantFields: []string{"status"},
+		},
+		{
+			name:       "unspecified outcome",
+			req:        &moderationpb.ResolveAppealRequest{AppealId: id},
+			wantFields: []string{"outcome"},
+		},
+		{
+			name: "message without rules",
+			req:  &socialpb.Review{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := testLimits.Validate(tt.req)
+			if tt.wantFields == nil {
+				assert.NoError(t, err)
+				return
+			}
+
+			assert.Equal(t, tt.wantFields, violatedFields(t, err))
+		})
+	}
+}
+
+func TestViolations_Err(t *testing.T) {
+	var v Violations
+	assert.NoError(t, v.Err())
+
+	v.Add("game_id", "must be a UUID")
+	v.Add("rating", "must be between 0 and 100")
+
+	err := v.Err()
+	assert.Equal(t, "invalid game_id: must be a UUID (and 1 more)", status.Convert(err).Message())
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	interceptor := UnaryServerInterceptor(testLimits)
+	info := &grpc.UnaryServerInfo{FullMethod: "/social.SocialService/CreateReview"}
+
+	called := false
+	handler := func(ctx context.Context, req any) (any, error) {
+		called = true
+		return nil, nil
+	}
+
+	_, err := interceptor(context.Background(), &socialpb.CreateReviewRequest{GameId: "bad"}, info, handler)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.False(t, called)
+
+	_, err = interceptor(context.Background(), &socialpb.CreateReviewRequest{GameId: uuid.NewString(), Rating: 50}, info, handler)
+	assert.NoError(t, err)
+	assert.True(t, called)
+}
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	msg *reviewspb.StreamReviewsRequest
+}
+
+func (s *fakeServerStream) RecvMsg(m any) error {
+	*m.(*reviewspb.StreamReviewsRequest) = reviewspb.StreamReviewsRequest{GameIds: s.msg.GameIds}
+	return nil
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	interceptor := StreamServerInterceptor(testLimits)
+	info := &grpc.StreamServerInfo{FullMethod: "/reviews.ReviewService/StreamReviews", IsServerStream: true}
+
+	handler := func(srv any, stream grpc.ServerStream) error {
+		return stream.RecvMsg(&reviewspb.StreamReviewsRequest{})
+	}
+
+	err := interceptor(nil, &fakeServerStream{msg: &reviewspb.StreamReviewsRequest{GameIds: []string{"bad"}}}, info, handler)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	err = interceptor(nil, &fakeServerStream{msg: &reviewspb.StreamReviewsRequest{GameIds: []string{uuid.NewString()}}}, info, handler)
+	assert.NoError(t, err)
+}
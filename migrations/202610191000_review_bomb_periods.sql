@@ -0,0 +1,24 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS social.review_bomb_periods (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    game_id UUID NOT NULL,
+    started_at TIMESTAMP NOT NULL,
+    ended_at TIMESTAMP,
+    window_reviews INTEGER NOT NULL,
+    window_average DOUBLE PRECISION NOT NULL,
+    baseline_reviews INTEGER NOT NULL,
+    baseline_average DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS review_bomb_periods_active_game
+    ON social.review_bomb_periods (game_id) WHERE ended_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS reviews_game_created_at
+    ON social.reviews (game_id, created_at);
+
+-- +goose Down
+
+DROP INDEX IF EXISTS social.reviews_game_created_at;
+DROP TABLE IF EXISTS social.review_bomb_periods;
@@ -0,0 +1,12 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS social.shadow_bans (
+    user_id UUID PRIMARY KEY,
+    reason TEXT,
+    banned_by UUID NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS social.shadow_bans;
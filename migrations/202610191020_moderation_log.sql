@@ -0,0 +1,40 @@
+-- +goose Up
+
+ALTER TABLE social.reviews
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'published'
+    CHECK (status IN ('published', 'hidden', 'deleted'));
+
+CREATE TABLE IF NOT EXISTS social.moderation_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID NOT NULL,
+    action TEXT NOT NULL,
+    target_type TEXT NOT NULL,
+    target_id UUID NOT NULL,
+    reason TEXT,
+    before JSONB,
+    after JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS moderation_log_created_at ON social.moderation_log (created_at DESC);
+CREATE INDEX IF NOT EXISTS moderation_log_target ON social.moderation_log (target_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS moderation_log_actor ON social.moderation_log (actor_id, created_at DESC);
+
+-- +goose StatementBegin
+CREATE OR REPLACE FUNCTION social.moderation_log_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'social.moderation_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+CREATE TRIGGER moderation_log_immutable
+    BEFORE UPDATE OR DELETE ON social.moderation_log
+    FOR EACH ROW EXECUTE FUNCTION social.moderation_log_immutable();
+
+-- +goose Down
+
+DROP TRIGGER IF EXISTS moderation_log_immutable ON social.moderation_log;
+DROP FUNCTION IF EXISTS social.moderation_log_immutable();
+DROP TABLE IF EXISTS social.moderation_log;
+ALTER TABLE social.reviews DROP COLUMN IF EXISTS status;
@@ -0,0 +1,23 @@
+-- +goose Up
+
+CREATE TABLE IF NOT EXISTS social.review_appeals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    review_id UUID NOT NULL REFERENCES social.reviews (id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    message TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'upheld', 'reinstated')),
+    removed_by UUID,
+    resolved_by UUID,
+    resolution_note TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    resolved_at TIMESTAMP,
+
+    CONSTRAINT unique_review_appeal UNIQUE (review_id),
+    CONSTRAINT appeal_resolver_differs CHECK (resolved_by IS NULL OR removed_by IS NULL OR resolved_by <> removed_by)
+);
+
+CREATE INDEX IF NOT EXISTS review_appeals_status_created_at ON social.review_appeals (status, created_at);
+
+-- +goose Down
+
+DROP TABLE IF EXISTS social.review_appeals;
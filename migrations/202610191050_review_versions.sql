@@ -0,0 +1,7 @@
+-- +goose Up
+
+ALTER TABLE social.reviews ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+-- +goose Down
+
+ALTER TABLE social.reviews DROP COLUMN IF EXISTS version;
@@ -0,0 +1,7 @@
+-- +goose Up
+
+ALTER TABLE social.idempotency_keys ADD COLUMN IF NOT EXISTS response_header JSONB;
+
+-- +goose Down
+
+ALTER TABLE social.idempotency_keys DROP COLUMN IF EXISTS response_header;